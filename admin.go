@@ -0,0 +1,273 @@
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"gg/config"
+	"gg/domain"
+	"gg/service"
+	"log"
+	"net/http"
+)
+
+// adminPage is what the admin page lists.
+type adminPage struct {
+	Events     []domain.Event
+	Watchlists []domain.Watchlist
+}
+
+type AdminHandler struct {
+	tracker   service.TrackerInterface
+	templates *templates
+	token     string
+	username  string
+	password  string
+}
+
+// handleAdmin registers the admin UI and API. They are only served when a
+// token or a username and password are configured.
+func handleAdmin(tracker service.TrackerInterface, templates *templates, adminConfig config.AdminConfig) {
+	h := &AdminHandler{
+		tracker:   tracker,
+		templates: templates,
+		token:     adminConfig.Token,
+		username:  adminConfig.Username,
+		password:  adminConfig.Password,
+	}
+	if h.token == "" && (h.username == "" || h.password == "") {
+		log.Println("Admin interface disabled. Set ADMIN_TOKEN or ADMIN_USERNAME and ADMIN_PASSWORD to enable it.")
+		return
+	}
+	eventPath := "/admin/api/events/tournament/{tournament}/event/{event}"
+	http.Handle("GET /admin", h.requireAuth(h.page))
+	http.Handle("GET /admin/api/events", h.requireAuth(h.listEvents))
+	http.Handle("POST /admin/api/events", h.requireAuth(h.addEvent))
+	http.Handle("PATCH "+eventPath, h.requireAuth(h.updateEvent))
+	http.Handle("DELETE "+eventPath, h.requireAuth(h.eventAction(h.tracker.RemoveEvent)))
+	http.Handle("POST "+eventPath+"/pause", h.requireAuth(h.eventAction(h.tracker.Pause)))
+	http.Handle("POST "+eventPath+"/resume", h.requireAuth(h.eventAction(h.tracker.Resume)))
+	http.Handle("POST "+eventPath+"/refresh", h.requireAuth(h.eventAction(h.tracker.Refresh)))
+	http.Handle("POST "+eventPath+"/export", h.requireAuth(h.eventAction(h.tracker.Export)))
+	http.Handle("GET /admin/api/watchlists", h.requireAuth(h.listWatchlists))
+	http.Handle("POST /admin/api/watchlists", h.requireAuth(h.addWatchlist))
+	http.Handle("PUT /admin/api/watchlists/{id}", h.requireAuth(h.updateWatchlist))
+	http.Handle("DELETE /admin/api/watchlists/{id}", h.requireAuth(h.removeWatchlist))
+	http.Handle("GET /admin/api/seasons", h.requireAuth(h.listSeasons))
+	http.Handle("POST /admin/api/seasons", h.requireAuth(h.addSeason))
+	http.Handle("PUT /admin/api/seasons/{id}", h.requireAuth(h.updateSeason))
+	http.Handle("DELETE /admin/api/seasons/{id}", h.requireAuth(h.removeSeason))
+}
+
+func secureCompare(given, expected string) bool {
+	return expected != "" && subtle.ConstantTimeCompare([]byte(given), []byte(expected)) == 1
+}
+
+// isAuthorized accepts the token as a bearer token or as a basic auth
+// password, so that browsers can log in with the token alone.
+func (h *AdminHandler) isAuthorized(r *http.Request) bool {
+	if auth := r.Header.Get("Authorization"); len(auth) > 7 && auth[:7] == "Bearer " {
+		return secureCompare(auth[7:], h.token)
+	}
+	username, password, ok := r.BasicAuth()
+	if !ok {
+		return false
+	}
+	if secureCompare(password, h.token) {
+		return true
+	}
+	return secureCompare(username, h.username) && secureCompare(password, h.password)
+}
+
+func (h *AdminHandler) requireAuth(next http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !h.isAuthorized(r) {
+			w.Header().Set("WWW-Authenticate", `Basic realm="gg admin"`)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("Error while encoding json. e=%s\n", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch err {
+	case service.ErrorEventNotFound, service.ErrorWatchlistNotFound, service.ErrorSeasonNotFound:
+		status = http.StatusNotFound
+	case service.ErrorEventAlreadyExists, service.ErrorWatchlistAlreadyExists, service.ErrorSeasonAlreadyExists:
+		status = http.StatusConflict
+	case service.ErrorEventSlugRequired, service.ErrorInvalidTimezone, service.ErrorWatchlistNameRequired, service.ErrorInvalidWebhookUrl,
+		service.ErrorSeasonNameRequired, service.ErrorInvalidSeasonEvent:
+		status = http.StatusBadRequest
+	}
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func (h *AdminHandler) page(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	h.templates.adminHTML.Execute(w, &adminPage{Events: h.tracker.GetEvents(), Watchlists: h.tracker.GetWatchlists()})
+}
+
+func (h *AdminHandler) listEvents(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, h.tracker.GetEvents())
+}
+
+func (h *AdminHandler) addEvent(w http.ResponseWriter, r *http.Request) {
+	var event domain.Event
+	if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	event.LastError, event.LastErrorAt, event.LastUpdatedAt = "", 0, 0
+	if err := h.tracker.AddEvent(event); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, event)
+}
+
+func (h *AdminHandler) updateEvent(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Title      *string   `json:"title"`
+		Timezone   *string   `json:"timezone"`
+		TimeFormat *string   `json:"timeFormat"`
+		Watchlists *[]string `json:"watchlists"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	slug := eventSlug(r)
+	event, err := h.tracker.GetEvent(slug)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if body.Timezone != nil || body.TimeFormat != nil {
+		timezone, timeFormat := event.Timezone, event.TimeFormat
+		if body.Timezone != nil {
+			timezone = *body.Timezone
+		}
+		if body.TimeFormat != nil {
+			timeFormat = *body.TimeFormat
+		}
+		if err := h.tracker.UpdateDisplay(slug, timezone, timeFormat); err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+	if body.Watchlists != nil {
+		if err := h.tracker.UpdateWatchlists(slug, *body.Watchlists); err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+	if body.Title != nil {
+		if err := h.tracker.UpdateTitle(slug, *body.Title); err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+	event, _ = h.tracker.GetEvent(slug)
+	writeJSON(w, http.StatusOK, event)
+}
+
+func (h *AdminHandler) eventAction(action func(slug string) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := action(eventSlug(r)); err != nil {
+			writeError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (h *AdminHandler) listWatchlists(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, h.tracker.GetWatchlists())
+}
+
+func (h *AdminHandler) addWatchlist(w http.ResponseWriter, r *http.Request) {
+	var watchlist domain.Watchlist
+	if err := json.NewDecoder(r.Body).Decode(&watchlist); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	res, err := h.tracker.AddWatchlist(watchlist)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, res)
+}
+
+func (h *AdminHandler) updateWatchlist(w http.ResponseWriter, r *http.Request) {
+	var watchlist domain.Watchlist
+	if err := json.NewDecoder(r.Body).Decode(&watchlist); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	watchlist.Id = r.PathValue("id")
+	if err := h.tracker.UpdateWatchlist(watchlist); err != nil {
+		writeError(w, err)
+		return
+	}
+	res, _ := h.tracker.GetWatchlist(watchlist.Id)
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (h *AdminHandler) removeWatchlist(w http.ResponseWriter, r *http.Request) {
+	if err := h.tracker.RemoveWatchlist(r.PathValue("id")); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *AdminHandler) listSeasons(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, h.tracker.GetSeasons())
+}
+
+func (h *AdminHandler) addSeason(w http.ResponseWriter, r *http.Request) {
+	var season domain.Season
+	if err := json.NewDecoder(r.Body).Decode(&season); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	res, err := h.tracker.AddSeason(season)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, res)
+}
+
+func (h *AdminHandler) updateSeason(w http.ResponseWriter, r *http.Request) {
+	var season domain.Season
+	if err := json.NewDecoder(r.Body).Decode(&season); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	season.Id = r.PathValue("id")
+	if err := h.tracker.UpdateSeason(season); err != nil {
+		writeError(w, err)
+		return
+	}
+	res, _ := h.tracker.GetSeason(season.Id)
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (h *AdminHandler) removeSeason(w http.ResponseWriter, r *http.Request) {
+	if err := h.tracker.RemoveSeason(r.PathValue("id")); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
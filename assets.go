@@ -0,0 +1,257 @@
+package main
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"gg/config"
+	"gg/domain"
+	"gg/formatter"
+	"gg/mapper"
+	htmltemplate "html/template"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+//go:embed template static
+var embeddedAssets embed.FS
+
+// overlayFS serves files from override when they exist there and falls back
+// to base otherwise, so a theme only needs to contain the files it changes.
+type overlayFS struct {
+	override fs.FS
+	base     fs.FS
+}
+
+func (o overlayFS) Open(name string) (fs.File, error) {
+	if o.override != nil {
+		f, err := o.override.Open(name)
+		if err == nil {
+			return f, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+	return o.base.Open(name)
+}
+
+func newAssetFS(dir, override string) fs.FS {
+	base, err := fs.Sub(embeddedAssets, dir)
+	if err != nil {
+		panic(err)
+	}
+	if override == "" {
+		return base
+	}
+	return overlayFS{override: os.DirFS(override), base: base}
+}
+
+type templates struct {
+	upsetThread     *htmltemplate.Template
+	upsetThreadHTML *htmltemplate.Template
+	markdown        *template.Template
+	markdownComment *template.Template
+	markdownDiscord *template.Template
+	bbcode          *template.Template
+	plaintext       *template.Template
+	indexHTML       *htmltemplate.Template
+	adminHTML       *htmltemplate.Template
+	overlayHTML     *htmltemplate.Template
+	watchlistHTML   *htmltemplate.Template
+	season          *template.Template
+	playerHTML      *htmltemplate.Template
+	characters      *template.Template
+	seeding         *template.Template
+	simulation      *template.Template
+	// displayOptions are keyed by output name, see config.Outputs.
+	displayOptions map[string]*mapper.DisplayOptions
+	formats        *formatter.Registry
+}
+
+// sampleUpsetThreadDisplay exercises every field of the display so that
+// templates referencing unknown fields fail at startup instead of on a request.
+var sampleUpsetThreadDisplay = &domain.UpsetThreadDisplay{
+	Host:          "localhost:8080",
+	Title:         "Title",
+	Slug:          "tournament/sample/event/sample",
+	LastUpdatedAt: "01/02/2006 03:04pm MST",
+	GrandFinals:   []*domain.UpsetThreadItemDisplay{{Content: "Grand final"}},
+	Winners:       []*domain.UpsetThreadItemDisplay{{Content: "Winner", Bold: true}},
+	Losers:        []*domain.UpsetThreadItemDisplay{{Content: "Loser"}},
+	Pools:         []*domain.UpsetThreadItemDisplay{{Content: "Pool"}},
+	Notables:      []*domain.UpsetThreadItemDisplay{{Content: "Notable"}},
+	DQs:           []*domain.UpsetThreadItemDisplay{{Content: "DQ"}},
+	Watched:       []*domain.UpsetThreadItemDisplay{{Content: "Watched"}},
+	Characters:    []string{"Steve players scored 7 upsets"},
+	Part:          2,
+	Parts:         3,
+}
+
+var sampleEvents = []domain.Event{{Slug: "tournament/sample/event/sample", Title: "Title", LastError: "error", Watchlists: []string{"sample"}}}
+
+var sampleWatchlist = domain.Watchlist{Id: "sample", Name: "Sample", Players: []string{"Player", "1234"}, WebhookUrl: "https://example.com"}
+
+var sampleScore = "3-1"
+
+var sampleUpsetThreads = []*domain.UpsetThread{{
+	Title: "Title",
+	Slug:  "tournament/sample/event/sample",
+	Winners: []domain.UpsetThreadItem{{
+		Id: "1", WinnersName: "Winner", WinnersCharacters: "Steve", WinnersSeed: 33, Score: &sampleScore,
+		LosersName: "Loser", LosersCharacters: "Kazuya", LosersSeed: 2, UpsetFactor: 7, CompletedAt: 1,
+	}},
+}}
+
+var sampleSeasonLeaderboard = domain.NewSeasonLeaderboard(
+	domain.Season{Id: "sample", Name: "Sample", Events: []string{"tournament/sample/event/sample"}},
+	sampleUpsetThreads,
+	domain.LeaderboardSize,
+)
+
+var sampleCharacterReport = domain.NewCharacterReport("Title", sampleUpsetThreads)
+
+var samplePlayerHistory = domain.NewPlayerHistory("Winner", sampleUpsetThreads)
+
+var sampleSeedingReport = domain.NewSeedingReport(sampleUpsetThreads[0], sampleUpsetThreads)
+
+var sampleSimulationReport = &domain.SimulationReport{
+	Title: "Title", Slug: "tournament/sample/event/sample", Model: domain.SimulationModelSeed,
+	Simulations: domain.DefaultSimulations, Sets: 3, Simulated: 2, Played: 1,
+	Factors: []domain.UpsetFactorExpectation{{UpsetFactor: 1, Expected: 0.5, Actual: 1, ExpectedAtLeast: 0.5, High: 1, ActualAtLeast: 1, ExpectedSoFar: 0.4}},
+}
+
+// reportFuncs are available to the templates of reports, such as season
+// leaderboards.
+var reportFuncs = template.FuncMap{
+	"inc":     func(i int) int { return i + 1 },
+	"percent": func(f float64) string { return fmt.Sprintf("%.0f%%", f*100) },
+}
+
+type executor interface {
+	Execute(w io.Writer, data any) error
+}
+
+func validateTemplate(name string, t executor, data any) error {
+	if err := t.Execute(io.Discard, data); err != nil {
+		return fmt.Errorf("template %s is invalid: %w", name, err)
+	}
+	return nil
+}
+
+// loadTemplates parses and validates every template, reporting all problems
+// at once.
+func loadTemplates(fsys fs.FS, cfg *config.Config) (*templates, error) {
+	var errs []error
+	parseText := func(name string) *template.Template {
+		t, err := template.ParseFS(fsys, name)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("template %s could not be parsed: %w", name, err))
+			return nil
+		}
+		if err := validateTemplate(name, t, sampleUpsetThreadDisplay); err != nil {
+			errs = append(errs, err)
+		}
+		return t
+	}
+	parseHTML := func(name string, sample any) *htmltemplate.Template {
+		t, err := htmltemplate.ParseFS(fsys, name)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("template %s could not be parsed: %w", name, err))
+			return nil
+		}
+		if err := validateTemplate(name, t, sample); err != nil {
+			errs = append(errs, err)
+		}
+		return t
+	}
+	parseReport := func(name string, sample any) *template.Template {
+		t, err := template.New(name).Funcs(reportFuncs).ParseFS(fsys, name)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("template %s could not be parsed: %w", name, err))
+			return nil
+		}
+		if err := validateTemplate(name, t, sample); err != nil {
+			errs = append(errs, err)
+		}
+		return t
+	}
+	res := &templates{
+		upsetThread:     parseHTML("upset-thread.tmpl", sampleUpsetThreadDisplay),
+		upsetThreadHTML: parseHTML("upset-thread.html", sampleUpsetThreadDisplay),
+		markdown:        parseText("markdown.tmpl"),
+		markdownComment: parseText("markdown-comment.tmpl"),
+		markdownDiscord: parseText("md-discord.tmpl"),
+		bbcode:          parseText("bbcode.tmpl"),
+		plaintext:       parseText("plaintext.tmpl"),
+		indexHTML:       parseHTML("index.html", sampleEvents),
+		adminHTML:       parseHTML("admin.html", &adminPage{Events: sampleEvents, Watchlists: []domain.Watchlist{sampleWatchlist}}),
+		overlayHTML:     parseHTML("overlay.html", &overlaySettings{Sections: overlaySections, Theme: "dark"}),
+		watchlistHTML:   parseHTML("watchlist.html", &domain.WatchlistDisplay{Id: "sample", Name: "Sample", Events: []*domain.UpsetThreadDisplay{sampleUpsetThreadDisplay}}),
+		season:          parseReport("season.tmpl", sampleSeasonLeaderboard),
+		playerHTML:      parseHTML("player.html", samplePlayerHistory),
+		characters:      parseReport("characters.tmpl", sampleCharacterReport),
+		seeding:         parseReport("seeding.tmpl", sampleSeedingReport),
+		simulation:      parseReport("simulation.tmpl", sampleSimulationReport),
+		displayOptions:  make(map[string]*mapper.DisplayOptions),
+	}
+	for _, output := range config.Outputs {
+		options, err := cfg.DisplayOptions(output)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		res.displayOptions[output] = options
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	res.formats = res.newRegistry()
+	return res, nil
+}
+
+const DiscordMessageLimit = 2000
+
+func (t *templates) newRegistry() *formatter.Registry {
+	registry := formatter.NewRegistry()
+	registry.Register("md-reddit", formatter.NewSplitTemplateFormatter(
+		"text/markdown; charset=utf-8",
+		t.displayOptions["md-reddit"],
+		mapper.NewThreadSplitter(renderText(t.markdown), renderText(t.markdownComment), mapper.RedditPostLimit, mapper.RedditCommentLimit),
+	))
+	registry.Register("md-discord", formatter.NewSplitTemplateFormatter(
+		"text/markdown; charset=utf-8",
+		t.displayOptions["md-discord"],
+		mapper.NewThreadSplitter(renderText(t.markdownDiscord), renderText(t.markdownDiscord), DiscordMessageLimit, DiscordMessageLimit),
+	))
+	registry.Register("bbcode", formatter.NewTemplateFormatter("text/plain; charset=utf-8", t.displayOptions["bbcode"], renderText(t.bbcode)))
+	registry.Register("plaintext", formatter.NewTemplateFormatter("text/plain; charset=utf-8", t.displayOptions["plaintext"], renderText(t.plaintext)))
+	registry.Register("html", formatter.NewTemplateFormatter("text/html; charset=utf-8", t.displayOptions["html"], renderText(t.upsetThreadHTML)))
+	registry.Register("csv", &formatter.CSVFormatter{})
+	registry.Register("json", &formatter.JSONFormatter{})
+	return registry
+}
+
+// renderText renders the display through a text or HTML template. HTML
+// templates escape the names and links entered on start.gg.
+func renderText(t executor) mapper.RenderFunc {
+	return func(display *domain.UpsetThreadDisplay) (string, error) {
+		var sb strings.Builder
+		err := t.Execute(&sb, display)
+		return sb.String(), err
+	}
+}
+
+func templateFS(templateDir string) fs.FS {
+	return newAssetFS("template", templateDir)
+}
+
+func staticFS(templateDir string) fs.FS {
+	if templateDir == "" {
+		return newAssetFS("static", "")
+	}
+	return newAssetFS("static", filepath.Join(templateDir, "static"))
+}
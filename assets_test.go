@@ -0,0 +1,214 @@
+package main
+
+import (
+	"fmt"
+	"gg/config"
+	"gg/domain"
+	"gg/mapper"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadEmbeddedTemplates(t *testing.T) {
+	if _, err := loadTemplates(templateFS(""), config.Default()); err != nil {
+		t.Errorf("Expected embedded templates to be valid, got %s", err)
+	}
+}
+
+func TestTemplateDirOverride(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "markdown.tmpl"), []byte("{{.Title}}"), 0o600); err != nil {
+		t.Fatalf("Error while writing template. e=%s", err)
+	}
+	fsys := templateFS(dir)
+	data, err := fs.ReadFile(fsys, "markdown.tmpl")
+	if err != nil || string(data) != "{{.Title}}" {
+		t.Errorf("Expected overridden markdown template, got %s %v", data, err)
+	}
+	if _, err := fs.ReadFile(fsys, "upset-thread.html"); err != nil {
+		t.Errorf("Expected embedded fallback for upset-thread.html, got %s", err)
+	}
+	if _, err := loadTemplates(fsys, config.Default()); err != nil {
+		t.Errorf("Expected templates to be valid, got %s", err)
+	}
+}
+
+func TestInvalidTemplates(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "markdown.tmpl"), []byte("{{.Title"), 0o600)
+	os.WriteFile(filepath.Join(dir, "upset-thread.tmpl"), []byte("{{.Unknown}}"), 0o600)
+	_, err := loadTemplates(templateFS(dir), config.Default())
+	if err == nil {
+		t.Fatalf("Expected invalid templates to fail")
+	}
+	for _, expected := range []string{"markdown.tmpl could not be parsed", "upset-thread.tmpl is invalid"} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("Expected error to mention %q, got %s", expected, err)
+		}
+	}
+}
+
+func TestStaticFS(t *testing.T) {
+	if _, err := fs.ReadFile(staticFS(t.TempDir()), "stylesheets/upset-thread.css"); err != nil {
+		t.Errorf("Expected embedded stylesheet, got %s", err)
+	}
+}
+
+func TestSplitLongThread(t *testing.T) {
+	templates, err := loadTemplates(templateFS(""), config.Default())
+	if err != nil {
+		t.Fatalf("Expected embedded templates to be valid, got %s", err)
+	}
+	upsetThread := &domain.UpsetThread{Title: "Title", Slug: "tournament/sample/event/sample"}
+	for i := 0; i < 2000; i++ {
+		upsetThread.Losers = append(upsetThread.Losers, domain.UpsetThreadItem{WinnersName: strings.Repeat("x", 40), UpsetFactor: i % 10})
+	}
+	markdown, err := templates.formats.Get("md-reddit")
+	if err != nil {
+		t.Fatalf("Expected md-reddit format, got %s", err)
+	}
+	parts, err := markdown.Format(upsetThread, "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if len(parts) < 3 {
+		t.Fatalf("Expected the thread to be split, got %d parts", len(parts))
+	}
+	if len(parts[0]) > mapper.RedditPostLimit || !strings.Contains(parts[0], fmt.Sprintf("parts 2 to %d", len(parts))) {
+		t.Errorf("Expected a post within the limit linking to the comments, got %d characters", len(parts[0]))
+	}
+	for i, part := range parts {
+		if header := fmt.Sprintf("**Part %d/%d**", i+1, len(parts)); !strings.HasPrefix(part, header) {
+			t.Errorf("Expected part %d to start with %s, got %.40q", i+1, header, part)
+		}
+	}
+	for _, part := range parts[1:] {
+		if len(part) > mapper.RedditCommentLimit || !strings.Contains(part, "# Losers (continued)") {
+			t.Errorf("Expected a comment within the limit with a continuation header, got %d characters", len(part))
+		}
+	}
+}
+
+func TestFormats(t *testing.T) {
+	templates, err := loadTemplates(templateFS(""), config.Default())
+	if err != nil {
+		t.Fatalf("Expected embedded templates to be valid, got %s", err)
+	}
+	score := "2-0"
+	upsetThread := &domain.UpsetThread{
+		Title:       "Title",
+		Slug:        "tournament/sample/event/sample",
+		Winners:     []domain.UpsetThreadItem{{WinnersName: "Mar", WinnersSeed: 62, Score: &score, LosersName: "Zomba", LosersSeed: 3, IsWinnersBracket: true, UpsetFactor: 9}},
+		Losers:      []domain.UpsetThreadItem{{Id: "1", WinnersName: "Sonix", WinnersSeed: 4, Score: &score, LosersName: "Tweek", LosersSeed: 1, VodUrl: "https://youtu.be/vod"}},
+		GrandFinals: []domain.UpsetThreadItem{{WinnersName: "Sonix", WinnersSeed: 4, Score: &score, LosersName: "Mar", LosersSeed: 62, IsWinnersBracket: true, GrandFinal: domain.GrandFinalReset}},
+		Pools:       []domain.UpsetThreadItem{{WinnersName: "Tweek", WinnersSeed: 40, Score: &score, LosersName: "Zomba", LosersSeed: 3, UpsetFactor: 7, BracketType: domain.BracketTypeRoundRobin, LosersRecord: "1-4"}},
+	}
+	expected := map[string]string{
+		"md-reddit":  "**Mar (seed 62) 2-0 Zomba (seed 3) - Upset Factor 9**",
+		"md-discord": "**Winners**\n**Mar (seed 62) 2-0 Zomba (seed 3) - Upset Factor 9**",
+		"bbcode":     "[*][b]Mar (seed 62) 2-0 Zomba (seed 3) - Upset Factor 9[/b]",
+		"plaintext":  "WINNERS\n\nMar (seed 62) 2-0 Zomba (seed 3) - Upset Factor 9",
+		"html":       "Mar (seed 62) 2-0 Zomba (seed 3) - Upset Factor 9",
+		"csv":        "winners,,Mar,,,,62,2-0,,Zomba,,,,3,0,true,9,0,,,,",
+		"json":       `"winnersName": "Mar"`,
+	}
+	for _, name := range templates.formats.Names() {
+		t.Run(name, func(t *testing.T) {
+			format, _ := templates.formats.Get(name)
+			parts, err := format.Format(upsetThread, "localhost:8080")
+			if err != nil {
+				t.Fatalf("Expected no error, got %s", err)
+			}
+			if len(parts) != 1 || !strings.Contains(parts[0], expected[name]) {
+				t.Errorf("Expected %q in %q", expected[name], parts)
+			}
+		})
+	}
+	for name, expected := range map[string]string{
+		"md-reddit": "# Losers\n\n[Sonix (seed 4) 2-0 Tweek (seed 1), out at 0th](https://www.start.gg/tournament/sample/event/sample/set/1) [🎥](https://youtu.be/vod)  \n\n# Pools\n\n**Tweek (seed 40) 2-0 Zomba (seed 3), went 1-4 in pools - Upset Factor 7**  \n\n# Notables",
+		"bbcode":    "[b]Pools[/b][/size]\n[list]\n[*][b]Tweek (seed 40) 2-0 Zomba (seed 3), went 1-4 in pools - Upset Factor 7[/b]\n[/list]",
+		"csv":       "pools,,Tweek,,,,40,2-0,,Zomba,,,,3,0,false,7,0,,,,,ROUND_ROBIN,,1-4",
+	} {
+		format, _ := templates.formats.Get(name)
+		if parts, _ := format.Format(upsetThread, ""); !strings.Contains(parts[0], expected) {
+			t.Errorf("Expected %s to show pool upsets with the loser's record, got %q", name, parts[0])
+		}
+	}
+	for name, expected := range map[string]string{
+		"md-reddit":  "# Grand Finals\n\nSonix (seed 4) 2-0 Mar (seed 62) - Sonix reset the bracket  \n\n# Winners",
+		"md-discord": "**Grand Finals**\nSonix (seed 4) 2-0 Mar (seed 62) - Sonix reset the bracket\n\n**Winners**",
+		"plaintext":  "GRAND FINALS\n\nSonix (seed 4) 2-0 Mar (seed 62) - Sonix reset the bracket\n\nWINNERS",
+		"csv":        "grandfinals,,Sonix,,,,4,2-0,,Mar,,,,62,0,true,0,0,,,,reset the bracket",
+	} {
+		format, _ := templates.formats.Get(name)
+		if parts, _ := format.Format(upsetThread, ""); !strings.Contains(parts[0], expected) {
+			t.Errorf("Expected %s to show grand finals first, got %q", name, parts[0])
+		}
+	}
+	for name, expected := range map[string]string{
+		"md-reddit": "[Sonix (seed 4) 2-0 Tweek (seed 1), out at 0th](https://www.start.gg/tournament/sample/event/sample/set/1) [🎥](https://youtu.be/vod)",
+		"html":      `<a href="https://youtu.be/vod" target="_blank" rel="noopener noreferrer" title="VOD">🎥</a>`,
+	} {
+		format, _ := templates.formats.Get(name)
+		if parts, _ := format.Format(upsetThread, ""); !strings.Contains(parts[0], expected) {
+			t.Errorf("Expected %s to link the set and VOD, got %s", name, parts[0])
+		}
+	}
+}
+
+func TestHTMLEscapesSets(t *testing.T) {
+	templates, err := loadTemplates(templateFS(""), config.Default())
+	if err != nil {
+		t.Fatalf("Expected embedded templates to be valid, got %s", err)
+	}
+	score := "2-0"
+	upsetThread := &domain.UpsetThread{
+		Title: "Title",
+		Slug:  "tournament/sample/event/sample",
+		Winners: []domain.UpsetThreadItem{{
+			Id: "1", WinnersName: "<script>alert(1)</script>", Score: &score, LosersName: "Zomba", IsWinnersBracket: true,
+			VodUrl: `https://youtu.be/vod?t="><script>alert(2)</script>`,
+		}},
+	}
+	html, _ := templates.formats.Get("html")
+	parts, err := html.Format(upsetThread, "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	var ws strings.Builder
+	if err := templates.upsetThread.Execute(&ws, mapper.ToDisplay(upsetThread, "")); err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	for _, res := range []string{parts[0], ws.String()} {
+		if strings.Contains(res, "<script>alert") {
+			t.Errorf("Expected the name and VOD link to be escaped, got %s", res)
+		}
+		if !strings.Contains(res, "&lt;script&gt;alert(1)&lt;/script&gt;") || !strings.Contains(res, `href="https://youtu.be/vod?t=%22%3e%3cscript%3ealert%282%29%3c/script%3e"`) {
+			t.Errorf("Expected the escaped name and VOD link, got %s", res)
+		}
+	}
+}
+
+func TestSeasonLeaderboardMarkdown(t *testing.T) {
+	templates, err := loadTemplates(templateFS(""), config.Default())
+	if err != nil {
+		t.Fatalf("Expected embedded templates to be valid, got %s", err)
+	}
+	var sb strings.Builder
+	if err := writeReport(&sb, templates.season, sampleSeasonLeaderboard, "markdown"); err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	for _, expected := range []string{
+		"# Sample",
+		"1. [Winner (seed 33) 3-1 Loser (seed 2)](https://www.start.gg/tournament/sample/event/sample/set/1) - Upset Factor 7 at Title",
+		"1. Winner - 1 upsets",
+		"1. Loser - upset 1 times",
+	} {
+		if !strings.Contains(sb.String(), expected) {
+			t.Errorf("Expected season markdown to contain %q, got %s", expected, sb.String())
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package card
+
+import (
+	"fmt"
+	"gg/domain"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"sort"
+	"strings"
+)
+
+const (
+	Width  = 1200
+	Height = 630
+	// DefaultTop is the number of upsets in a summary image.
+	DefaultTop = 10
+	MaxTop     = 25
+)
+
+var (
+	background = color.RGBA{0x16, 0x16, 0x1d, 0xff}
+	panel      = color.RGBA{0x24, 0x24, 0x30, 0xff}
+	foreground = color.RGBA{0xf5, 0xf5, 0xf7, 0xff}
+	muted      = color.RGBA{0xa0, 0xa0, 0xb0, 0xff}
+)
+
+// BadgeColor grades the upset factor from grey for expected results to red
+// for the biggest upsets.
+func BadgeColor(upsetFactor int) color.RGBA {
+	switch {
+	case upsetFactor >= 8:
+		return color.RGBA{0xd7, 0x26, 0x3d, 0xff}
+	case upsetFactor >= 4:
+		return color.RGBA{0xf4, 0x60, 0x36, 0xff}
+	case upsetFactor >= 1:
+		return color.RGBA{0xf2, 0xc1, 0x4e, 0xff}
+	default:
+		return color.RGBA{0x6c, 0x75, 0x7d, 0xff}
+	}
+}
+
+func fill(img draw.Image, r image.Rectangle, c color.Color) {
+	draw.Draw(img, r, &image.Uniform{c}, image.Point{}, draw.Src)
+}
+
+// textWidth is the width in pixels of s drawn at the given scale.
+func textWidth(s string, scale int) int {
+	n := len([]rune(s))
+	if n == 0 {
+		return 0
+	}
+	return (n*(glyphWidth+1) - 1) * scale
+}
+
+// fitText spells s in the font's characters and shortens it with an
+// ellipsis until it is at most maxWidth wide.
+func fitText(s string, scale, maxWidth int) string {
+	s = transliterate(s)
+	runes := []rune(s)
+	if textWidth(s, scale) <= maxWidth {
+		return s
+	}
+	for len(runes) > 0 && textWidth(string(runes)+"...", scale) > maxWidth {
+		runes = runes[:len(runes)-1]
+	}
+	return strings.TrimSpace(string(runes)) + "..."
+}
+
+// drawText draws s with its top left corner at x, y. Characters outside the
+// font are drawn as '?'.
+func drawText(img draw.Image, x, y int, s string, scale int, c color.Color) {
+	for i, r := range []rune(s) {
+		if r < firstGlyph || r > lastGlyph {
+			r = '?'
+		}
+		left := x + i*(glyphWidth+1)*scale
+		for row, bits := range glyphs[r-firstGlyph] {
+			for col := 0; col < glyphWidth; col++ {
+				if bits&(1<<(glyphWidth-1-col)) == 0 {
+					continue
+				}
+				px, py := left+col*scale, y+row*scale
+				fill(img, image.Rect(px, py, px+scale, py+scale), c)
+			}
+		}
+	}
+}
+
+func drawCentered(img draw.Image, r image.Rectangle, y int, s string, scale int, c color.Color) {
+	s = fitText(s, scale, r.Dx())
+	drawText(img, r.Min.X+(r.Dx()-textWidth(s, scale))/2, y, s, scale, c)
+}
+
+func score(item domain.UpsetThreadItem) string {
+	if item.Score == nil || *item.Score == "" {
+		return "-"
+	}
+	return *item.Score
+}
+
+func playerDetails(seed int, characters string) string {
+	if characters == "" {
+		return fmt.Sprintf("Seed %d", seed)
+	}
+	return fmt.Sprintf("Seed %d, %s", seed, characters)
+}
+
+// Card draws a single set: the winner over the loser with their seeds and
+// the score, and a badge coloured by the upset factor.
+func Card(item domain.UpsetThreadItem, title string) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, Width, Height))
+	fill(img, img.Bounds(), background)
+	badgeColor := BadgeColor(item.UpsetFactor)
+	fill(img, image.Rect(0, 0, 16, Height), badgeColor)
+
+	textWidthLimit := 760
+	drawText(img, 60, 50, fitText(title, 4, textWidthLimit), 4, muted)
+	bracket := "LOSERS BRACKET"
+	if item.BracketType.IsPoolStage() {
+		bracket = strings.ToUpper(item.BracketType.StageName())
+	} else if item.IsWinnersBracket {
+		bracket = "WINNERS BRACKET"
+	}
+	drawText(img, 60, 100, bracket, 3, muted)
+
+	drawText(img, 60, 200, fitText(item.WinnersName, 8, textWidthLimit), 8, foreground)
+	drawText(img, 60, 274, fitText(playerDetails(item.WinnersSeed, item.WinnersCharacters), 3, textWidthLimit), 3, muted)
+	drawText(img, 60, 334, fitText(score(item), 7, textWidthLimit), 7, badgeColor)
+	drawText(img, 60, 420, fitText(item.LosersName, 8, textWidthLimit), 8, foreground)
+	drawText(img, 60, 494, fitText(playerDetails(item.LosersSeed, item.LosersCharacters), 3, textWidthLimit), 3, muted)
+
+	badge := image.Rect(880, 50, 1140, 290)
+	fill(img, badge, panel)
+	fill(img, image.Rect(badge.Min.X, badge.Min.Y, badge.Max.X, badge.Min.Y+12), badgeColor)
+	drawCentered(img, badge, 90, "UPSET FACTOR", 3, muted)
+	drawCentered(img, badge, 140, fmt.Sprint(item.UpsetFactor), 14, badgeColor)
+	return img
+}
+
+// TopUpsets returns up to n winners bracket, losers bracket, pool and grand
+// final upsets with the highest upset factors.
+func TopUpsets(upsetThread *domain.UpsetThread, n int) []domain.UpsetThreadItem {
+	items := append(append(append([]domain.UpsetThreadItem(nil), upsetThread.Winners...), upsetThread.Losers...), upsetThread.Pools...)
+	for _, item := range upsetThread.GrandFinals {
+		if item.UpsetFactor > 0 {
+			items = append(items, item)
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].UpsetFactor > items[j].UpsetFactor
+	})
+	if len(items) > n {
+		items = items[:n]
+	}
+	return items
+}
+
+// Summary draws the top n upsets of the event, one row each.
+func Summary(upsetThread *domain.UpsetThread, n int) *image.RGBA {
+	items := TopUpsets(upsetThread, n)
+	rowHeight := 70
+	height := 160 + max(len(items), 1)*rowHeight + 30
+	img := image.NewRGBA(image.Rect(0, 0, Width, height))
+	fill(img, img.Bounds(), background)
+
+	drawText(img, 40, 40, fitText(upsetThread.Title, 5, Width-80), 5, foreground)
+	drawText(img, 40, 100, fmt.Sprintf("TOP %d UPSETS", len(items)), 3, muted)
+	if len(items) == 0 {
+		drawText(img, 40, 160, "No upsets yet", 4, muted)
+	}
+	for i, item := range items {
+		y := 160 + i*rowHeight
+		badgeColor := BadgeColor(item.UpsetFactor)
+		badge := image.Rect(40, y, 140, y+54)
+		fill(img, badge, badgeColor)
+		drawCentered(img, badge, y+13, fmt.Sprint(item.UpsetFactor), 4, background)
+		line := fmt.Sprintf("%s (%d) %s %s (%d)", item.WinnersName, item.WinnersSeed, score(item), item.LosersName, item.LosersSeed)
+		drawText(img, 170, y+13, fitText(line, 4, Width-210), 4, foreground)
+	}
+	return img
+}
+
+func Encode(w io.Writer, img image.Image) error {
+	return png.Encode(w, img)
+}
+
+// FindItem looks up a set by its ID across every section of the thread.
+func FindItem(upsetThread *domain.UpsetThread, setId string) (domain.UpsetThreadItem, bool) {
+	for _, items := range [][]domain.UpsetThreadItem{upsetThread.GrandFinals, upsetThread.Winners, upsetThread.Losers, upsetThread.Pools, upsetThread.Notables, upsetThread.DQs, upsetThread.Other} {
+		for _, item := range items {
+			if item.Id == setId {
+				return item, true
+			}
+		}
+	}
+	return domain.UpsetThreadItem{}, false
+}
@@ -0,0 +1,110 @@
+package card
+
+import (
+	"bytes"
+	"gg/domain"
+	"image/png"
+	"testing"
+)
+
+var threeTwo = "3-2"
+
+var upsetThread = &domain.UpsetThread{
+	Title: "Supernova",
+	Winners: []domain.UpsetThreadItem{
+		{Id: "1", WinnersName: "Mar", WinnersSeed: 62, Score: &threeTwo, LosersName: "Zomba", LosersSeed: 3, IsWinnersBracket: true, UpsetFactor: 9},
+		{Id: "2", WinnersName: "Light", WinnersSeed: 9, Score: &threeTwo, LosersName: "Tweek", LosersSeed: 5, IsWinnersBracket: true, UpsetFactor: 2},
+	},
+	Losers: []domain.UpsetThreadItem{{Id: "3", WinnersName: "Sonix", WinnersSeed: 17, LosersName: "Glutonny", LosersSeed: 6, UpsetFactor: 4}},
+	Other:  []domain.UpsetThreadItem{{Id: "4", WinnersName: "MkLeo", LosersName: "Riddles"}},
+}
+
+type badgeColorTestCase struct {
+	upsetFactor int
+	expected    uint8
+}
+
+var badgeColorTestCases = []badgeColorTestCase{
+	{9, 0xd7},
+	{4, 0xf4},
+	{1, 0xf2},
+	{0, 0x6c},
+}
+
+func TestBadgeColor(t *testing.T) {
+	for _, tc := range badgeColorTestCases {
+		if res := BadgeColor(tc.upsetFactor); res.R != tc.expected {
+			t.Errorf("Expected red %x for upset factor %d, got %x", tc.expected, tc.upsetFactor, res.R)
+		}
+	}
+}
+
+func TestCard(t *testing.T) {
+	item, ok := FindItem(upsetThread, "1")
+	if !ok {
+		t.Fatalf("Expected set 1 to be found")
+	}
+	img := Card(item, upsetThread.Title)
+	if img.Bounds().Dx() != Width || img.Bounds().Dy() != Height {
+		t.Errorf("Expected %dx%d card, got %v", Width, Height, img.Bounds())
+	}
+	if img.RGBAAt(0, 0) != BadgeColor(9) {
+		t.Errorf("Expected the accent bar in the badge colour, got %v", img.RGBAAt(0, 0))
+	}
+	var buf bytes.Buffer
+	if err := Encode(&buf, img); err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if _, err := png.Decode(&buf); err != nil {
+		t.Errorf("Expected a valid PNG, got %s", err)
+	}
+}
+
+func TestFindItem(t *testing.T) {
+	if item, ok := FindItem(upsetThread, "4"); !ok || item.WinnersName != "MkLeo" {
+		t.Errorf("Expected set 4 from other sets, got %v %t", item, ok)
+	}
+	if _, ok := FindItem(upsetThread, "5"); ok {
+		t.Errorf("Expected set 5 not to be found")
+	}
+}
+
+func TestSummary(t *testing.T) {
+	top := TopUpsets(upsetThread, 2)
+	if len(top) != 2 || top[0].Id != "1" || top[1].Id != "3" {
+		t.Errorf("Expected sets 1 and 3, got %v", top)
+	}
+	if res := Summary(upsetThread, 2).Bounds().Dy(); res != 330 {
+		t.Errorf("Expected a summary of two rows, got height %d", res)
+	}
+}
+
+func TestFitText(t *testing.T) {
+	if res := fitText("Supernova", 1, textWidth("Supernova", 1)); res != "Supernova" {
+		t.Errorf("Expected text that fits to be unchanged, got %s", res)
+	}
+	if res := fitText("Supernova", 1, textWidth("Super...", 1)); res != "Super..." {
+		t.Errorf("Expected Super..., got %s", res)
+	}
+}
+
+func TestTransliterate(t *testing.T) {
+	tests := []struct {
+		s        string
+		expected string
+	}{
+		{"Mäx", "Max"},
+		{"Sonix", "Sonix"},
+		{"Łukasz Ørsted", "Lukasz Orsted"},
+		{"Æther’s Straße", "AEther's Strasse"},
+		{"ファンキー", "ファンキー"},
+	}
+	for _, test := range tests {
+		if res := transliterate(test.s); res != test.expected {
+			t.Errorf("Expected %s, got %s", test.expected, res)
+		}
+	}
+	if res := fitText("Pépé", 1, 100); res != "Pepe" {
+		t.Errorf("Expected the text to be drawn without accents, got %s", res)
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"gg/card"
+	"gg/service"
+	"image"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type CardHandler struct {
+	tracker service.TrackerInterface
+}
+
+// parseTop reads the number of upsets for a summary image, defaulting to
+// card.DefaultTop and capped at card.MaxTop.
+func parseTop(s string) int {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 1 {
+		return card.DefaultTop
+	}
+	return min(n, card.MaxTop)
+}
+
+// ServeHTTP serves cards/{setId}.png for a single set and cards/top.png?n=
+// for a summary of the event's biggest upsets.
+func (h *CardHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name, ok := strings.CutSuffix(r.PathValue("card"), ".png")
+	if !ok {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+	upsetThread, err := h.tracker.GetUpsetThread(eventSlug(r))
+	if err != nil {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+	var img image.Image
+	if name == "top" {
+		img = card.Summary(upsetThread, parseTop(r.URL.Query().Get("n")))
+	} else {
+		item, ok := card.FindItem(upsetThread, name)
+		if !ok {
+			http.Error(w, "Not found", http.StatusNotFound)
+			return
+		}
+		img = card.Card(item, upsetThread.Title)
+	}
+	var buf bytes.Buffer
+	if err := card.Encode(&buf, img); err != nil {
+		log.Printf("Error while encoding card. e=%s\n", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "image/png")
+	w.Write(buf.Bytes())
+}
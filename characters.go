@@ -0,0 +1,43 @@
+package main
+
+import (
+	"gg/service"
+	"log"
+	"net/http"
+)
+
+type CharacterHandler struct {
+	tracker   service.TrackerInterface
+	templates *templates
+}
+
+// handleCharacters registers the character statistics of each tracked event
+// and of every event with stored sets.
+func handleCharacters(tracker service.TrackerInterface, templates *templates) {
+	h := &CharacterHandler{tracker: tracker, templates: templates}
+	http.HandleFunc("GET /characters", h.allEvents)
+	http.HandleFunc("GET /event/tournament/{tournament}/event/{event}/characters", h.event)
+}
+
+func (h *CharacterHandler) write(w http.ResponseWriter, r *http.Request, slug string) {
+	format, ok := reportFormat(w, r)
+	if !ok {
+		return
+	}
+	report, err := h.tracker.GetCharacterReport(slug)
+	if err != nil {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+	if err := writeReport(w, h.templates.characters, report, format); err != nil {
+		log.Printf("Error while rendering characters. slug=%s e=%s\n", slug, err)
+	}
+}
+
+func (h *CharacterHandler) allEvents(w http.ResponseWriter, r *http.Request) {
+	h.write(w, r, service.AllEvents)
+}
+
+func (h *CharacterHandler) event(w http.ResponseWriter, r *http.Request) {
+	h.write(w, r, eventSlug(r))
+}
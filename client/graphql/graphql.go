@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
 )
 
@@ -23,6 +22,22 @@ type Client struct {
 	httpClient HttpClientInterface
 }
 
+// StatusError is returned when the API answers with a status of 400 or
+// above.
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("api returned an error status. status_code=%d", e.StatusCode)
+}
+
+// Retryable reports whether the request may succeed when sent again, as for
+// server errors and rate limiting.
+func (e *StatusError) Retryable() bool {
+	return e.StatusCode >= 500 || e.StatusCode == http.StatusTooManyRequests
+}
+
 type Payload struct {
 	Query     string      `json:"query"`
 	Variables interface{} `json:"variables"`
@@ -32,31 +47,27 @@ func (client *Client) Query(query string, variables interface{}) ([]byte, error)
 	payload := Payload{query, variables}
 	body, err := json.Marshal(payload)
 	if err != nil {
-		log.Fatalf("Error while marshaling. e=%s\n", err)
+		return nil, fmt.Errorf("error while marshaling: %w", err)
 	}
 	req, err := http.NewRequest("POST", client.url, bytes.NewBuffer(body))
 	if err != nil {
-		log.Fatalf("Error while creating new request. e=%s\n", err)
+		return nil, fmt.Errorf("error while creating new request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+client.apiToken)
 	resp, err := client.httpClient.Do(req)
 	if err != nil {
-		log.Fatalf("Error on http client. e=%s\n", err)
+		return nil, fmt.Errorf("error on http client: %w", err)
 	}
 	defer resp.Body.Close()
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		log.Fatalf("Error on io read. e=%s\n", err)
-	}
-
-	if resp.StatusCode >= 500 {
-		return nil, fmt.Errorf("api returned a status >= 500. status_code=%d", resp.StatusCode)
+		return nil, fmt.Errorf("error on io read: %w", err)
 	}
 
-	if resp.StatusCode >= 400 && resp.StatusCode < 500 {
-		log.Fatalf("API returned a status between [400, 500). status_code=%d\n", resp.StatusCode)
+	if resp.StatusCode >= 400 {
+		return nil, &StatusError{resp.StatusCode}
 	}
 
 	return respBody, nil
@@ -2,6 +2,7 @@ package graphql
 
 import (
 	"bytes"
+	"errors"
 	"io"
 	"net/http"
 	"testing"
@@ -9,12 +10,21 @@ import (
 
 type FakeHttpClient struct {
 	doMethodCalled bool
+	statusCode     int
+	err            error
 }
 
 func (client *FakeHttpClient) Do(*http.Request) (*http.Response, error) {
 	client.doMethodCalled = true
+	if client.err != nil {
+		return nil, client.err
+	}
+	statusCode := client.statusCode
+	if statusCode == 0 {
+		statusCode = 200
+	}
 	return &http.Response{
-		StatusCode: 200,
+		StatusCode: statusCode,
 		Body:       io.NopCloser(bytes.NewBufferString("")),
 	}, nil
 }
@@ -45,6 +55,29 @@ func TestQuery(t *testing.T) {
 	}
 }
 
+func TestQueryErrors(t *testing.T) {
+	tests := []struct {
+		httpClient *FakeHttpClient
+		retryable  bool
+	}{
+		{&FakeHttpClient{statusCode: 429}, true},
+		{&FakeHttpClient{statusCode: 503}, true},
+		{&FakeHttpClient{statusCode: 401}, false},
+	}
+	for _, test := range tests {
+		client := Client{"url", "apiToken", test.httpClient}
+		_, err := client.Query("query", nil)
+		var statusErr *StatusError
+		if !errors.As(err, &statusErr) || statusErr.StatusCode != test.httpClient.statusCode || statusErr.Retryable() != test.retryable {
+			t.Errorf("Expected a status error for %d, got %v", test.httpClient.statusCode, err)
+		}
+	}
+	client := Client{"url", "apiToken", &FakeHttpClient{err: errors.New("connection refused")}}
+	if _, err := client.Query("query", nil); err == nil {
+		t.Errorf("Expected the http client error to be returned")
+	}
+}
+
 func TestNewClient(t *testing.T) {
 	client := NewClient("url", "apiToken", &FakeHttpClient{})
 	if client.url != "url" {
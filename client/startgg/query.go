@@ -14,6 +14,13 @@ var eventsQuery string = `
 			videogame {
 				slug
 			}
+			tournament {
+				timezone
+			}
+			phases {
+				id
+				phaseOrder
+			}
 			sets(filters: $filters page: $page sortType: $sortType) {
 				pageInfo {
 					total
@@ -26,10 +33,17 @@ var eventsQuery string = `
 				nodes {
 					id
 					completedAt
+					vodUrl
+					stream {
+						streamName
+						streamSource
+					}
 					games {
 						id
 						winnerId
 						orderNum
+						entrant1Score
+						entrant2Score
 						selections {
 							orderNum
 							selectionType
@@ -56,9 +70,24 @@ var eventsQuery string = `
 					setGamesType
 					round
 					phaseGroup {
+						id
 						displayIdentifier
+						bracketType
+						phase {
+							id
+							name
+							groupCount
+						}
 					}
 					slots {
+						standing {
+							stats {
+								score {
+									value
+									displayValue
+								}
+							}
+						}
 						entrant {
 							id
 							name
@@ -67,6 +96,48 @@ var eventsQuery string = `
 								isFinal
 								placement
 							}
+							participants {
+								prefix
+								gamerTag
+								player {
+									id
+								}
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+`
+var bracketQuery string = `
+	query BracketQuery(
+			$slug: String
+			$filters: SetFilters
+			$page: Int
+	) {
+		event(slug: $slug) {
+			sets(filters: $filters page: $page sortType: STANDARD) {
+				pageInfo {
+					totalPages
+				}
+				nodes {
+					id
+					slots {
+						prereqType
+						prereqId
+						prereqPlacement
+						entrant {
+							id
+							name
+							initialSeedNum
+							participants {
+								prefix
+								gamerTag
+								player {
+									id
+								}
+							}
 						}
 					}
 				}
@@ -3,6 +3,7 @@ package startgg
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"gg/client/graphql"
 	"log"
 	"math"
@@ -18,11 +19,14 @@ var ErrorGreaterthan10KEntry = errors.New("cannot query more than 10,000th entry
 
 type ClientInterface interface {
 	GetEvent(slug string, page int) (*EventResponse, error)
-	GetCharacters(slug string) CharactersResponse
+	GetBracket(slug string, page int) (*BracketResponse, error)
+	GetCharacters(slug string) (*CharactersResponse, error)
 }
 
 type Client struct {
 	graphQLClient graphql.ClientInterface
+	maxRetries    int
+	baseDelay     time.Duration
 }
 
 type Entrant struct {
@@ -33,6 +37,13 @@ type Entrant struct {
 		IsFinal   bool `json:"isFinal"`
 		Placement int  `json:"placement"`
 	} `json:"standing"`
+	Participants []struct {
+		Prefix   string `json:"prefix"`
+		GamerTag string `json:"gamerTag"`
+		Player   struct {
+			Id int `json:"id"`
+		} `json:"player"`
+	} `json:"participants"`
 }
 
 type Selection struct {
@@ -43,15 +54,18 @@ type Selection struct {
 }
 
 type Game struct {
-	Id         int         `json:"id"`
-	WinnerId   int         `json:"winnerId"`
-	OrderNum   int         `json:"orderNum"`
-	Selections []Selection `json:"selections"`
+	Id            int         `json:"id"`
+	WinnerId      int         `json:"winnerId"`
+	OrderNum      int         `json:"orderNum"`
+	Entrant1Score *int        `json:"entrant1Score"`
+	Entrant2Score *int        `json:"entrant2Score"`
+	Selections    []Selection `json:"selections"`
 }
 
 type Node struct {
 	Id            int    `json:"id"`
 	CompletedAt   int    `json:"completedAt"`
+	VodUrl        string `json:"vodUrl"`
 	Games         []Game `json:"games"`
 	Identifier    string `json:"identifier"`
 	DisplayScore  string `json:"displayScore"`
@@ -64,11 +78,30 @@ type Node struct {
 	SetGamesType  int    `json:"setGamesType"`
 	Round         int    `json:"round"`
 	PhaseGroup    struct {
+		Id                int    `json:"id"`
 		DisplayIdentifier string `json:"displayIdentifier"`
+		BracketType       string `json:"bracketType"`
+		Phase             struct {
+			Id         int    `json:"id"`
+			Name       string `json:"name"`
+			GroupCount int    `json:"groupCount"`
+		} `json:"phase"`
 	} `json:"phaseGroup"`
 	Slots []struct {
+		Standing *struct {
+			Stats struct {
+				Score struct {
+					Value        *float64 `json:"value"`
+					DisplayValue string   `json:"displayValue"`
+				} `json:"score"`
+			} `json:"stats"`
+		} `json:"standing"`
 		Entrant Entrant `json:"entrant"`
 	} `json:"slots"`
+	Stream *struct {
+		StreamName   string `json:"streamName"`
+		StreamSource string `json:"streamSource"`
+	} `json:"stream"`
 }
 
 type EventResponse struct {
@@ -80,6 +113,13 @@ type EventResponse struct {
 			Videogame struct {
 				Slug string `json:"slug"`
 			} `json:"videogame"`
+			Tournament struct {
+				Timezone string `json:"timezone"`
+			} `json:"tournament"`
+			Phases []struct {
+				Id         int `json:"id"`
+				PhaseOrder int `json:"phaseOrder"`
+			} `json:"phases"`
 			Sets struct {
 				PageInfo struct {
 					Total      int    `json:"total"`
@@ -93,9 +133,75 @@ type EventResponse struct {
 			} `json:"sets"`
 		} `json:"event"`
 	} `json:"data"`
-	Errors []struct {
-		Message string `json:"message"`
-	} `json:"errors"`
+	Errors []ResponseError `json:"errors"`
+}
+
+type ResponseError struct {
+	Message string `json:"message"`
+}
+
+// SetId is the ID of a set, a number for sets that were created and a string
+// such as "preview_123_1_1" for the sets of brackets that were not started.
+type SetId string
+
+func (id *SetId) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*id = SetId(s)
+		return nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return err
+	}
+	*id = SetId(n.String())
+	return nil
+}
+
+// BracketNode is a set of the bracket, played or not. Each slot is filled by
+// a seeded entrant or by the winner, for PrereqPlacement 1, or the loser, for
+// 2, of the set PrereqId when PrereqType is "set".
+type BracketNode struct {
+	Id    SetId `json:"id"`
+	Slots []struct {
+		PrereqType      string   `json:"prereqType"`
+		PrereqId        string   `json:"prereqId"`
+		PrereqPlacement int      `json:"prereqPlacement"`
+		Entrant         *Entrant `json:"entrant"`
+	} `json:"slots"`
+}
+
+type BracketResponse struct {
+	Data struct {
+		Event struct {
+			Sets struct {
+				PageInfo struct {
+					TotalPages int `json:"totalPages"`
+				} `json:"pageInfo"`
+				Nodes []BracketNode `json:"nodes"`
+			} `json:"sets"`
+		} `json:"event"`
+	} `json:"data"`
+	Errors []ResponseError `json:"errors"`
+}
+
+// queryError returns the first error of a response, and whether the query is
+// worth retrying.
+func queryError(errs []ResponseError) (error, bool) {
+	if errs == nil {
+		return nil, false
+	}
+	if errs[0].Message == "Cannot query more than the 10,000th entry" {
+		return ErrorGreaterthan10KEntry, false
+	}
+	return errors.New(errs[0].Message), true
+}
+
+// isRetryable reports whether a failed query is worth sending again. Only
+// server errors and rate limiting are among the error statuses.
+func isRetryable(err error) bool {
+	var statusErr *graphql.StatusError
+	return !errors.As(err, &statusErr) || statusErr.Retryable()
 }
 
 func (client *Client) getEvent(slug string, page int) (*EventResponse, error, bool) {
@@ -110,44 +216,87 @@ func (client *Client) getEvent(slug string, page int) (*EventResponse, error, bo
 	}
 	resp, err := client.graphQLClient.Query(eventsQuery, variables{slug, page, filters{3}, "RECENT"})
 	if err != nil {
-		return nil, err, true
+		return nil, err, isRetryable(err)
 	}
 	var eventResponse EventResponse
 	if err := json.Unmarshal(resp, &eventResponse); err != nil {
-		log.Fatalf("Error while unmarshaling event. e=%s\n", err)
+		return nil, fmt.Errorf("error while unmarshaling event: %w", err), true
 	}
-	if eventResponse.Errors != nil {
-		if eventResponse.Errors[0].Message == "Cannot query more than the 10,000th entry" {
-			return &eventResponse, ErrorGreaterthan10KEntry, false
-		}
-		return &eventResponse, errors.New(eventResponse.Errors[0].Message), true
-	}
-	return &eventResponse, nil, false
+	err, retryable := queryError(eventResponse.Errors)
+	return &eventResponse, err, retryable
 }
 
-func (client *Client) GetEvent(slug string, page int) (*EventResponse, error) {
-	var eventResponse *EventResponse
+// withRetries runs the query until it succeeds or fails with an error that is
+// not worth retrying, waiting twice as long after each attempt.
+func (client *Client) withRetries(query func() (error, bool)) error {
 	var err error
 	var retryable bool
 
-	for i := 0; i < MAX_RETRIES; i++ {
-		eventResponse, err, retryable = client.getEvent(slug, page)
+	for i := 0; i < client.maxRetries; i++ {
+		err, retryable = query()
 		if err == nil || !retryable {
 			break
 		}
 
 		secRetry := math.Pow(2, float64(i))
-		delay := time.Duration(secRetry) * BASE_DELAY
-		log.Printf("Error: %s. Retrying %d of %d\n in %v seconds", err, i+1, MAX_RETRIES, delay.Seconds())
+		delay := time.Duration(secRetry) * client.baseDelay
+		log.Printf("Error: %s. Retrying %d of %d\n in %v seconds", err, i+1, client.maxRetries, delay.Seconds())
 		time.Sleep(delay)
 	}
+	return err
+}
 
+func (client *Client) GetEvent(slug string, page int) (*EventResponse, error) {
+	var eventResponse *EventResponse
+	err := client.withRetries(func() (error, bool) {
+		var err error
+		var retryable bool
+		eventResponse, err, retryable = client.getEvent(slug, page)
+		return err, retryable
+	})
 	if err != nil {
 		return nil, err
 	}
 	return eventResponse, nil
 }
 
+func (client *Client) getBracket(slug string, page int) (*BracketResponse, error, bool) {
+	type filters struct {
+		ShowByes bool `json:"showByes"`
+	}
+	type variables struct {
+		Slug    string  `json:"slug"`
+		Page    int     `json:"page"`
+		Filters filters `json:"filters"`
+	}
+	resp, err := client.graphQLClient.Query(bracketQuery, variables{slug, page, filters{true}})
+	if err != nil {
+		return nil, err, isRetryable(err)
+	}
+	var bracketResponse BracketResponse
+	if err := json.Unmarshal(resp, &bracketResponse); err != nil {
+		return nil, fmt.Errorf("error while unmarshaling bracket: %w", err), true
+	}
+	err, retryable := queryError(bracketResponse.Errors)
+	return &bracketResponse, err, retryable
+}
+
+// GetBracket returns a page of every set of the event, including those not
+// played yet, to find how entrants advance through the bracket.
+func (client *Client) GetBracket(slug string, page int) (*BracketResponse, error) {
+	var bracketResponse *BracketResponse
+	err := client.withRetries(func() (error, bool) {
+		var err error
+		var retryable bool
+		bracketResponse, err, retryable = client.getBracket(slug, page)
+		return err, retryable
+	})
+	if err != nil {
+		return nil, err
+	}
+	return bracketResponse, nil
+}
+
 type Character struct {
 	Id   int    `json:"id"`
 	Name string `json:"name"`
@@ -163,19 +312,26 @@ type CharactersResponse struct {
 	} `json:"data"`
 }
 
-func (client *Client) GetCharacters(slug string) CharactersResponse {
+func (client *Client) GetCharacters(slug string) (*CharactersResponse, error) {
 	log.Println("Getting characters")
 	type variables struct {
 		Slug string `json:"slug"`
 	}
-	resp, _ := client.graphQLClient.Query(charactersQuery, variables{slug})
+	resp, err := client.graphQLClient.Query(charactersQuery, variables{slug})
+	if err != nil {
+		return nil, err
+	}
 	var charactersResponse CharactersResponse
 	if err := json.Unmarshal(resp, &charactersResponse); err != nil {
-		log.Fatalf("Error while unmarshaling characters. e=%s\n", err)
+		return nil, fmt.Errorf("error while unmarshaling characters: %w", err)
 	}
-	return charactersResponse
+	return &charactersResponse, nil
 }
 
-func NewClient(graphQLClient graphql.ClientInterface) *Client {
-	return &Client{graphQLClient: graphQLClient}
+func NewClient(graphQLClient graphql.ClientInterface, maxRetries int, baseDelay time.Duration) *Client {
+	return &Client{
+		graphQLClient: graphQLClient,
+		maxRetries:    maxRetries,
+		baseDelay:     baseDelay,
+	}
 }
@@ -1,20 +1,27 @@
 package startgg
 
-import "testing"
+import (
+	"errors"
+	"gg/client/graphql"
+	"testing"
+)
 
 type FakeGraphQLClient struct {
 	queryMethodCalled bool
+	queries           int
 	returnValue       []byte
+	err               error
 }
 
 func (client *FakeGraphQLClient) Query(string, interface{}) ([]byte, error) {
 	client.queryMethodCalled = true
-	return client.returnValue, nil
+	client.queries++
+	return client.returnValue, client.err
 }
 
 func TestGetEvent(t *testing.T) {
 	fakeGraphQLClient := FakeGraphQLClient{returnValue: []byte(`{ "data": { "event": { "videogame": {}, "sets": {} } } }`)}
-	client := NewClient(&fakeGraphQLClient)
+	client := NewClient(&fakeGraphQLClient, MAX_RETRIES, BASE_DELAY)
 	client.GetEvent("slug", 1)
 	if !fakeGraphQLClient.queryMethodCalled {
 		t.Errorf("Expected query method to be called")
@@ -23,9 +30,47 @@ func TestGetEvent(t *testing.T) {
 
 func TestGetCharacters(t *testing.T) {
 	fakeGraphQLClient := FakeGraphQLClient{returnValue: []byte(`{ "data": { "videogame": {} } }`)}
-	client := NewClient(&fakeGraphQLClient)
-	client.GetCharacters("slug")
+	client := NewClient(&fakeGraphQLClient, MAX_RETRIES, BASE_DELAY)
+	if _, err := client.GetCharacters("slug"); err != nil {
+		t.Errorf("Expected no error, got %s", err)
+	}
 	if !fakeGraphQLClient.queryMethodCalled {
 		t.Errorf("Expected query method to be called")
 	}
 }
+
+func TestGetEventErrors(t *testing.T) {
+	tests := []struct {
+		err     error
+		queries int
+	}{
+		{&graphql.StatusError{StatusCode: 401}, 1},
+		{&graphql.StatusError{StatusCode: 429}, 3},
+	}
+	for _, test := range tests {
+		fakeGraphQLClient := FakeGraphQLClient{err: test.err}
+		client := NewClient(&fakeGraphQLClient, 3, 0)
+		if _, err := client.GetEvent("slug", 1); !errors.Is(err, test.err) {
+			t.Errorf("Expected %s, got %v", test.err, err)
+		}
+		if fakeGraphQLClient.queries != test.queries {
+			t.Errorf("Expected %d queries for %s, got %d", test.queries, test.err, fakeGraphQLClient.queries)
+		}
+	}
+	client := NewClient(&FakeGraphQLClient{returnValue: []byte("<html>")}, 1, 0)
+	if _, err := client.GetEvent("slug", 1); err == nil {
+		t.Errorf("Expected an error for a response that is not JSON")
+	}
+}
+
+func TestGetBracket(t *testing.T) {
+	fakeGraphQLClient := FakeGraphQLClient{returnValue: []byte(`{ "data": { "event": { "sets": { "nodes": [{ "id": "preview_1_1_1" }, { "id": 123 }] } } } }`)}
+	client := NewClient(&fakeGraphQLClient, MAX_RETRIES, BASE_DELAY)
+	res, err := client.GetBracket("slug", 1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if nodes := res.Data.Event.Sets.Nodes; len(nodes) != 2 || nodes[0].Id != "preview_1_1_1" || nodes[1].Id != "123" {
+		t.Errorf("Expected preview and created set IDs, got %+v", nodes)
+	}
+}
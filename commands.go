@@ -0,0 +1,253 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"gg/card"
+	"gg/client/graphql"
+	"gg/client/startgg"
+	"gg/config"
+	"gg/db"
+	"gg/domain"
+	"gg/mapper"
+	"gg/service"
+	"image"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func runConfigCommand(args []string) {
+	if len(args) == 0 || args[0] != "print" {
+		fmt.Fprintln(os.Stderr, "usage: gg config print [flags]")
+		os.Exit(2)
+	}
+	cfg, err := config.Load("gg config print", args[1:], os.Getenv)
+	if cfg == nil {
+		log.Fatalf("Error while loading config. e=%s\n", err)
+	}
+	res, marshalErr := json.MarshalIndent(cfg.Redacted(), "", "  ")
+	if marshalErr != nil {
+		log.Fatalf("Error while marshaling config. e=%s\n", marshalErr)
+	}
+	fmt.Println(string(res))
+	if err == nil {
+		err = cfg.Validate()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid config.\n%s\n", err)
+		os.Exit(1)
+	}
+}
+
+// loadEventConfig loads the config of a command that works on the stored
+// upset thread of the --slug event.
+func loadEventConfig(name string, args []string) *config.Config {
+	cfg, err := config.Load(name, args, os.Getenv)
+	if err != nil {
+		log.Fatalf("Error while loading config. e=%s\n", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid config.\n%s\n", err)
+	}
+	if cfg.Event.Slug == "" {
+		log.Fatalf("Error while loading event. e=--slug is required\n")
+	}
+	return cfg
+}
+
+// loadStoredUpsetThread reads the event's upset thread from redis without
+// polling start.gg.
+func loadStoredUpsetThread(cfg *config.Config) *domain.UpsetThread {
+	dbService := db.NewRedisDBService(*redis.NewClient(&redis.Options{Addr: cfg.Redis.URL}), context.Background())
+	upsetThreadService := service.NewService(dbService, nil, &service.FileReaderWriter{}, time.Duration(cfg.StartGG.PageDelay), cfg.NotableRules())
+	upsetThread := upsetThreadService.GetUpsetThreadDB(cfg.Event.Slug, cfg.Event.Title)
+	if stored, ok := (*dbService.GetEvents())[cfg.Event.Slug]; ok {
+		event := mapper.DBEventToEvent(stored)
+		if upsetThread.Title == "" {
+			upsetThread.Title = event.Title
+		}
+		event.ApplyTo(upsetThread)
+		storedWatchlists := *dbService.GetWatchlists()
+		var watchlists []domain.Watchlist
+		for _, id := range event.Watchlists {
+			if watchlist, ok := storedWatchlists[id]; ok {
+				watchlists = append(watchlists, *mapper.DBWatchlistToWatchlist(watchlist))
+			}
+		}
+		if len(watchlists) > 0 {
+			upsetThread.Watched = domain.WatchedItems(upsetThread, watchlists)
+		}
+	}
+	return upsetThread
+}
+
+// runFormatCommand prints the stored upset thread of the --slug event in the
+// given format.
+func runFormatCommand(args []string) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		fmt.Fprintln(os.Stderr, "usage: gg format <format> --slug <slug> [flags]")
+		os.Exit(2)
+	}
+	cfg := loadEventConfig("gg format", args[1:])
+	templates, err := loadTemplates(templateFS(cfg.TemplateDir), cfg)
+	if err != nil {
+		log.Fatalf("Invalid templates.\n%s\n", err)
+	}
+	format, err := templates.formats.Get(args[0])
+	if err != nil {
+		log.Fatalf("Error while formatting event. e=%s\n", err)
+	}
+	parts, err := format.Format(loadStoredUpsetThread(cfg), cfg.Addr)
+	if err != nil {
+		log.Fatalf("Error while formatting event. e=%s\n", err)
+	}
+	fmt.Print(strings.Join(parts, "\n"))
+}
+
+// runCardCommand writes the PNG card of a set, or the summary of the top
+// upsets for "top", to stdout.
+func runCardCommand(args []string) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		fmt.Fprintln(os.Stderr, "usage: gg card <setId|top> --slug <slug> [flags] > card.png")
+		os.Exit(2)
+	}
+	cfg := loadEventConfig("gg card", args[1:])
+	upsetThread := loadStoredUpsetThread(cfg)
+	var img image.Image
+	if args[0] == "top" {
+		img = card.Summary(upsetThread, card.DefaultTop)
+	} else {
+		item, ok := card.FindItem(upsetThread, args[0])
+		if !ok {
+			log.Fatalf("Error while rendering card. e=set %s not found\n", args[0])
+		}
+		img = card.Card(item, upsetThread.Title)
+	}
+	if err := card.Encode(os.Stdout, img); err != nil {
+		log.Fatalf("Error while rendering card. e=%s\n", err)
+	}
+}
+
+// runSeasonCommand prints the leaderboards of a stored season as markdown or
+// JSON, reading the stored upset threads of its events from redis.
+func runSeasonCommand(args []string) {
+	if len(args) < 2 || (args[0] != "markdown" && args[0] != "json") || strings.HasPrefix(args[1], "-") {
+		fmt.Fprintln(os.Stderr, "usage: gg season <markdown|json> <id> [flags]")
+		os.Exit(2)
+	}
+	cfg, err := config.Load("gg season", args[2:], os.Getenv)
+	if err != nil {
+		log.Fatalf("Error while loading config. e=%s\n", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid config.\n%s\n", err)
+	}
+	templates, err := loadTemplates(templateFS(cfg.TemplateDir), cfg)
+	if err != nil {
+		log.Fatalf("Invalid templates.\n%s\n", err)
+	}
+	dbService := db.NewRedisDBService(*redis.NewClient(&redis.Options{Addr: cfg.Redis.URL}), context.Background())
+	stored, ok := (*dbService.GetSeasons())[args[1]]
+	if !ok {
+		log.Fatalf("Error while loading season. e=season %s not found\n", args[1])
+	}
+	titles := make(map[string]string)
+	for slug, event := range *dbService.GetEvents() {
+		titles[slug] = mapper.DBEventToEvent(event).Title
+	}
+	upsetThreadService := service.NewService(dbService, nil, &service.FileReaderWriter{}, time.Duration(cfg.StartGG.PageDelay), cfg.NotableRules())
+	leaderboard := service.SeasonLeaderboard(upsetThreadService.GetUpsetThreadDB, *mapper.DBSeasonToSeason(stored), titles)
+	if err := writeReport(os.Stdout, templates.season, leaderboard, args[0]); err != nil {
+		log.Fatalf("Error while rendering season. e=%s\n", err)
+	}
+}
+
+// runSeedingCommand prints the seeding report of the --slug event as markdown
+// or JSON, benchmarked against every other event with stored sets in redis.
+func runSeedingCommand(args []string) {
+	if len(args) == 0 || (args[0] != "markdown" && args[0] != "json") {
+		fmt.Fprintln(os.Stderr, "usage: gg seeding <markdown|json> --slug <slug> [flags]")
+		os.Exit(2)
+	}
+	cfg := loadEventConfig("gg seeding", args[1:])
+	templates, err := loadTemplates(templateFS(cfg.TemplateDir), cfg)
+	if err != nil {
+		log.Fatalf("Invalid templates.\n%s\n", err)
+	}
+	dbService := db.NewRedisDBService(*redis.NewClient(&redis.Options{Addr: cfg.Redis.URL}), context.Background())
+	titles := make(map[string]string)
+	for slug, event := range *dbService.GetEvents() {
+		titles[slug] = mapper.DBEventToEvent(event).Title
+	}
+	if cfg.Event.Title != "" {
+		titles[cfg.Event.Slug] = cfg.Event.Title
+	}
+	upsetThreadService := service.NewService(dbService, nil, &service.FileReaderWriter{}, time.Duration(cfg.StartGG.PageDelay), cfg.NotableRules())
+	report, err := service.SeedingReport(upsetThreadService.GetUpsetThreadDB, dbService.GetSetSlugs(), titles, cfg.Event.Slug)
+	if err != nil {
+		log.Fatalf("Error while loading event. e=no stored sets for %s\n", cfg.Event.Slug)
+	}
+	if err := writeReport(os.Stdout, templates.seeding, report, args[0]); err != nil {
+		log.Fatalf("Error while rendering seeding. e=%s\n", err)
+	}
+}
+
+// runSimulateCommand plays out the bracket of the --slug event from start.gg
+// and prints the expected upsets next to those stored in redis, as markdown or
+// JSON.
+func runSimulateCommand(args []string) {
+	usage := func() {
+		fmt.Fprintln(os.Stderr, "usage: gg simulate <markdown|json> <seed|rating> [simulations] --slug <slug> [flags]")
+		os.Exit(2)
+	}
+	if len(args) < 2 || (args[0] != "markdown" && args[0] != "json") {
+		usage()
+	}
+	options := domain.SimulationOptions{Model: args[1], Simulations: domain.DefaultSimulations}
+	flags := args[2:]
+	if len(flags) > 0 && !strings.HasPrefix(flags[0], "-") {
+		simulations, err := strconv.Atoi(flags[0])
+		if err != nil {
+			usage()
+		}
+		options.Simulations, flags = simulations, flags[1:]
+	}
+	if err := options.Validate(); err != nil {
+		log.Fatalf("Error while simulating event. e=%s\n", err)
+	}
+	cfg := loadEventConfig("gg simulate", flags)
+	templates, err := loadTemplates(templateFS(cfg.TemplateDir), cfg)
+	if err != nil {
+		log.Fatalf("Invalid templates.\n%s\n", err)
+	}
+	dbService := db.NewRedisDBService(*redis.NewClient(&redis.Options{Addr: cfg.Redis.URL}), context.Background())
+	upsetThreadService := service.NewService(
+		dbService,
+		startgg.NewClient(
+			graphql.NewClient(cfg.StartGG.APIURL, cfg.StartGG.APIKey, &http.Client{}),
+			cfg.StartGG.MaxRetries,
+			time.Duration(cfg.StartGG.BaseDelay),
+		),
+		&service.FileReaderWriter{},
+		time.Duration(cfg.StartGG.PageDelay),
+		cfg.NotableRules(),
+	)
+	bracket, err := upsetThreadService.GetBracket(cfg.Event.Slug)
+	if err != nil {
+		log.Fatalf("Error while getting bracket. e=%s\n", err)
+	}
+	report, err := service.SimulationReport(loadStoredUpsetThread(cfg), bracket, dbService, options)
+	if err != nil {
+		log.Fatalf("Error while simulating event. e=%s\n", err)
+	}
+	if err := writeReport(os.Stdout, templates.simulation, report, args[0]); err != nil {
+		log.Fatalf("Error while rendering simulation. e=%s\n", err)
+	}
+}
@@ -11,4 +11,23 @@ type DBServiceInterface interface {
 	SetIsCharactersLoaded(slug string)
 	AddSets(slug string, setMapping *map[string]string)
 	GetSets(slug string) *map[string]string
+	GetSetSlugs() []string
+	AddEvent(slug string, event string)
+	RemoveEvent(slug string)
+	GetEvents() *map[string]string
+	SetEventInfo(slug, field, value string)
+	GetEventInfo(slug, field string) string
+	AddWatchlist(id string, watchlist string)
+	RemoveWatchlist(id string)
+	GetWatchlists() *map[string]string
+	AddNotifiedSet(id, setKey string) bool
+	RemoveNotifiedSet(id, setKey string)
+	AddSeason(id string, season string)
+	RemoveSeason(id string)
+	GetSeasons() *map[string]string
+	AddRatedSet(setKey string) bool
+	GetPlayerRating(player string) string
+	SetPlayerRating(player string, rating string)
+	GetHeadToHead(players string) string
+	SetHeadToHead(players string, history string)
 }
@@ -4,7 +4,9 @@ import (
 	"context"
 	"gg/client/startgg"
 	"log"
+	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/redis/go-redis/v9"
 )
@@ -57,8 +59,13 @@ func (r *RedisDBService) SetIsCharactersLoaded(slug string) {
 }
 
 func (r *RedisDBService) AddSets(slug string, setMapping *map[string]string) {
-	for setId, s := range *setMapping {
-		r.AddSet(slug, setId, s)
+	setIds := make([]string, 0, len(*setMapping))
+	for setId := range *setMapping {
+		setIds = append(setIds, setId)
+	}
+	sort.Strings(setIds)
+	for _, setId := range setIds {
+		r.AddSet(slug, setId, (*setMapping)[setId])
 	}
 }
 
@@ -73,3 +80,162 @@ func (r *RedisDBService) GetSets(slug string) *map[string]string {
 	setMapping := r.rdb.HGetAll(r.ctx, "event:"+slug+"_sets").Val()
 	return &setMapping
 }
+
+// GetSetSlugs returns the slug of every event with stored sets, whether or
+// not it is still tracked.
+func (r *RedisDBService) GetSetSlugs() []string {
+	var slugs []string
+	iter := r.rdb.Scan(r.ctx, 0, "event:*_sets", 0).Iterator()
+	for iter.Next(r.ctx) {
+		slugs = append(slugs, strings.TrimSuffix(strings.TrimPrefix(iter.Val(), "event:"), "_sets"))
+	}
+	if err := iter.Err(); err != nil {
+		log.Fatalf("Error while scanning sets. e=%s\n", err)
+	}
+	sort.Strings(slugs)
+	return slugs
+}
+
+func (r *RedisDBService) AddEvent(slug string, event string) {
+	err := r.rdb.HSet(r.ctx, "events", slug, event).Err()
+	if err != nil {
+		log.Fatalf("Error while adding event. e=%s\n", err)
+	}
+}
+
+func (r *RedisDBService) RemoveEvent(slug string) {
+	err := r.rdb.HDel(r.ctx, "events", slug).Err()
+	if err != nil {
+		log.Fatalf("Error while removing event. e=%s\n", err)
+	}
+}
+
+func (r *RedisDBService) GetEvents() *map[string]string {
+	eventMapping := r.rdb.HGetAll(r.ctx, "events").Val()
+	return &eventMapping
+}
+
+func (r *RedisDBService) SetEventInfo(slug, field, value string) {
+	err := r.rdb.HSet(r.ctx, "event:"+slug+"_info", field, value).Err()
+	if err != nil {
+		log.Fatalf("Error while setting event info. e=%s\n", err)
+	}
+}
+
+func (r *RedisDBService) GetEventInfo(slug, field string) string {
+	val, err := r.rdb.HGet(r.ctx, "event:"+slug+"_info", field).Result()
+	if err == redis.Nil {
+		return ""
+	}
+	if err != nil {
+		log.Fatalf("Error while getting event info. e=%s\n", err)
+	}
+	return val
+}
+
+func (r *RedisDBService) AddWatchlist(id string, watchlist string) {
+	err := r.rdb.HSet(r.ctx, "watchlists", id, watchlist).Err()
+	if err != nil {
+		log.Fatalf("Error while adding watchlist. e=%s\n", err)
+	}
+}
+
+func (r *RedisDBService) RemoveWatchlist(id string) {
+	err := r.rdb.HDel(r.ctx, "watchlists", id).Err()
+	if err != nil {
+		log.Fatalf("Error while removing watchlist. e=%s\n", err)
+	}
+	err = r.rdb.Del(r.ctx, "watchlist:"+id+"_notified").Err()
+	if err != nil {
+		log.Fatalf("Error while removing notified sets. e=%s\n", err)
+	}
+}
+
+func (r *RedisDBService) GetWatchlists() *map[string]string {
+	watchlistMapping := r.rdb.HGetAll(r.ctx, "watchlists").Val()
+	return &watchlistMapping
+}
+
+// AddNotifiedSet records that the watchlist was notified of the set. It
+// returns false when it already was.
+func (r *RedisDBService) AddNotifiedSet(id, setKey string) bool {
+	added, err := r.rdb.SAdd(r.ctx, "watchlist:"+id+"_notified", setKey).Result()
+	if err != nil {
+		log.Fatalf("Error while adding notified set. e=%s\n", err)
+	}
+	return added == 1
+}
+
+// RemoveNotifiedSet forgets that the watchlist was notified of the set, so
+// that it is notified again.
+func (r *RedisDBService) RemoveNotifiedSet(id, setKey string) {
+	err := r.rdb.SRem(r.ctx, "watchlist:"+id+"_notified", setKey).Err()
+	if err != nil {
+		log.Fatalf("Error while removing notified set. e=%s\n", err)
+	}
+}
+
+func (r *RedisDBService) AddSeason(id string, season string) {
+	err := r.rdb.HSet(r.ctx, "seasons", id, season).Err()
+	if err != nil {
+		log.Fatalf("Error while adding season. e=%s\n", err)
+	}
+}
+
+func (r *RedisDBService) RemoveSeason(id string) {
+	err := r.rdb.HDel(r.ctx, "seasons", id).Err()
+	if err != nil {
+		log.Fatalf("Error while removing season. e=%s\n", err)
+	}
+}
+
+func (r *RedisDBService) GetSeasons() *map[string]string {
+	seasonMapping := r.rdb.HGetAll(r.ctx, "seasons").Val()
+	return &seasonMapping
+}
+
+// AddRatedSet records that the set updated the ratings of its players. It
+// returns false when it already did.
+func (r *RedisDBService) AddRatedSet(setKey string) bool {
+	added, err := r.rdb.SAdd(r.ctx, "rated_sets", setKey).Result()
+	if err != nil {
+		log.Fatalf("Error while adding rated set. e=%s\n", err)
+	}
+	return added == 1
+}
+
+func (r *RedisDBService) GetPlayerRating(player string) string {
+	val, err := r.rdb.HGet(r.ctx, "ratings", player).Result()
+	if err == redis.Nil {
+		return ""
+	}
+	if err != nil {
+		log.Fatalf("Error while getting player rating. e=%s\n", err)
+	}
+	return val
+}
+
+func (r *RedisDBService) SetPlayerRating(player string, rating string) {
+	err := r.rdb.HSet(r.ctx, "ratings", player, rating).Err()
+	if err != nil {
+		log.Fatalf("Error while setting player rating. e=%s\n", err)
+	}
+}
+
+func (r *RedisDBService) GetHeadToHead(players string) string {
+	val, err := r.rdb.HGet(r.ctx, "head_to_head", players).Result()
+	if err == redis.Nil {
+		return ""
+	}
+	if err != nil {
+		log.Fatalf("Error while getting head-to-head. e=%s\n", err)
+	}
+	return val
+}
+
+func (r *RedisDBService) SetHeadToHead(players string, history string) {
+	err := r.rdb.HSet(r.ctx, "head_to_head", players, history).Err()
+	if err != nil {
+		log.Fatalf("Error while setting head-to-head. e=%s\n", err)
+	}
+}
@@ -64,7 +64,7 @@ func TestGetSets(t *testing.T) {
 		"123": "hello_how_are_you",
 		"456": "fine_how_about_you",
 	}
-	mock.ExpectHGetAll("event:tournament/supernova-2024/event/ultimate-1-1-singles_sets").SetVal(storedSets)
+	mock.ExpectHGetAll("event:tournament/supernova-2024/event/ultimate-1v1-singles_sets").SetVal(storedSets)
 	sets := redisDBService.GetSets("tournament/supernova-2024/event/ultimate-1v1-singles")
 
 	for key, val := range *sets {
@@ -73,3 +73,113 @@ func TestGetSets(t *testing.T) {
 		}
 	}
 }
+
+func TestAddEvent(t *testing.T) {
+	mock.ExpectHSet("events", "tournament/supernova-2024/event/ultimate-1v1-singles", "event").SetVal(1)
+	redisDBService.AddEvent("tournament/supernova-2024/event/ultimate-1v1-singles", "event")
+}
+
+func TestRemoveEvent(t *testing.T) {
+	mock.ExpectHDel("events", "tournament/supernova-2024/event/ultimate-1v1-singles").SetVal(1)
+	redisDBService.RemoveEvent("tournament/supernova-2024/event/ultimate-1v1-singles")
+}
+
+func TestGetEvents(t *testing.T) {
+	storedEvents := map[string]string{
+		"tournament/supernova-2024/event/ultimate-1v1-singles": "event",
+	}
+	mock.ExpectHGetAll("events").SetVal(storedEvents)
+	events := redisDBService.GetEvents()
+
+	for key, val := range *events {
+		if val != storedEvents[key] {
+			t.Errorf("Expected val=%s for key=%s, got %s", storedEvents[key], key, val)
+		}
+	}
+}
+
+func TestSetEventInfo(t *testing.T) {
+	mock.ExpectHSet("event:tournament/supernova-2024/event/ultimate-1v1-singles_info", "timezone", "America/New_York").SetVal(1)
+	redisDBService.SetEventInfo("tournament/supernova-2024/event/ultimate-1v1-singles", "timezone", "America/New_York")
+}
+
+func TestGetEventInfo(t *testing.T) {
+	mock.ExpectHGet("event:tournament/supernova-2024/event/ultimate-1v1-singles_info", "timezone").SetVal("America/New_York")
+	timezone := redisDBService.GetEventInfo("tournament/supernova-2024/event/ultimate-1v1-singles", "timezone")
+
+	if timezone != "America/New_York" {
+		t.Errorf("Expected timezone=America/New_York, got %v\n", timezone)
+	}
+}
+
+func TestGetEventInfoNotFound(t *testing.T) {
+	mock.ExpectHGet("event:tournament/supernova-2024/event/ultimate-1v1-singles_info", "timezone").RedisNil()
+	timezone := redisDBService.GetEventInfo("tournament/supernova-2024/event/ultimate-1v1-singles", "timezone")
+
+	if timezone != "" {
+		t.Errorf("Expected empty timezone, got %v\n", timezone)
+	}
+}
+
+func TestRemoveWatchlist(t *testing.T) {
+	mock.ExpectHDel("watchlists", "team").SetVal(1)
+	mock.ExpectDel("watchlist:team_notified").SetVal(1)
+	redisDBService.RemoveWatchlist("team")
+}
+
+func TestAddNotifiedSet(t *testing.T) {
+	mock.ExpectSAdd("watchlist:team_notified", "tournament/supernova-2024/event/ultimate-1v1-singles/123").SetVal(1)
+	mock.ExpectSAdd("watchlist:team_notified", "tournament/supernova-2024/event/ultimate-1v1-singles/123").SetVal(0)
+
+	if !redisDBService.AddNotifiedSet("team", "tournament/supernova-2024/event/ultimate-1v1-singles/123") {
+		t.Errorf("Expected the first notification to be added")
+	}
+	if redisDBService.AddNotifiedSet("team", "tournament/supernova-2024/event/ultimate-1v1-singles/123") {
+		t.Errorf("Expected a repeated notification not to be added")
+	}
+}
+
+func TestRemoveNotifiedSet(t *testing.T) {
+	mock.ExpectSRem("watchlist:team_notified", "tournament/supernova-2024/event/ultimate-1v1-singles/123").SetVal(1)
+	redisDBService.RemoveNotifiedSet("team", "tournament/supernova-2024/event/ultimate-1v1-singles/123")
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expected the notified set to be removed, got %s", err)
+	}
+}
+
+func TestGetSetSlugs(t *testing.T) {
+	mock.ExpectScan(0, "event:*_sets", 0).SetVal([]string{"event:tournament/b/event/singles_sets", "event:tournament/a/event/singles_sets"}, 0)
+	slugs := redisDBService.GetSetSlugs()
+
+	if len(slugs) != 2 || slugs[0] != "tournament/a/event/singles" || slugs[1] != "tournament/b/event/singles" {
+		t.Errorf("Expected both event slugs in order, got %v\n", slugs)
+	}
+}
+
+func TestAddRatedSet(t *testing.T) {
+	mock.ExpectSAdd("rated_sets", "tournament/a/event/singles/123").SetVal(1)
+	mock.ExpectSAdd("rated_sets", "tournament/a/event/singles/123").SetVal(0)
+
+	if !redisDBService.AddRatedSet("tournament/a/event/singles/123") {
+		t.Errorf("Expected the first rating to be added")
+	}
+	if redisDBService.AddRatedSet("tournament/a/event/singles/123") {
+		t.Errorf("Expected a repeated rating not to be added")
+	}
+}
+
+func TestGetHeadToHeadNotFound(t *testing.T) {
+	mock.ExpectHGet("head_to_head", "1234 vs 5678").RedisNil()
+
+	if history := redisDBService.GetHeadToHead("1234 vs 5678"); history != "" {
+		t.Errorf("Expected no head-to-head, got %s", history)
+	}
+}
+
+func TestGetPlayerRatingNotFound(t *testing.T) {
+	mock.ExpectHGet("ratings", "1234").RedisNil()
+
+	if rating := redisDBService.GetPlayerRating("1234"); rating != "" {
+		t.Errorf("Expected no rating, got %s", rating)
+	}
+}
@@ -0,0 +1,13 @@
+package db
+
+import _ "embed"
+
+// SampleSets are start.gg set nodes from a finished event and
+// SampleCharacters the characters of its game. They are used to preview
+// output without tracking a live event.
+var (
+	//go:embed test_data.json
+	SampleSets []byte
+	//go:embed characters.json
+	SampleCharacters []byte
+)
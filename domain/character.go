@@ -0,0 +1,154 @@
+package domain
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// CharacterStats counts the sets of a character across the report's events.
+// A set counts once for each character its entrant played in it.
+type CharacterStats struct {
+	Character  string `json:"character"`
+	Sets       int    `json:"sets"`
+	UpsetsWon  int    `json:"upsetsWon"`
+	UpsetsLost int    `json:"upsetsLost"`
+	// AverageUpsetFactor is the average upset factor of the upsets won.
+	AverageUpsetFactor float64 `json:"averageUpsetFactor"`
+	// NotableRate is the share of the sets that were notable, including
+	// upsets that would have been notable had the favourite won.
+	NotableRate      float64 `json:"notableRate"`
+	totalUpsetFactor int
+	notables         int
+}
+
+// CharacterMatchup is a cell of the character against character upset
+// matrix: the upsets of Winner players over Loser players.
+type CharacterMatchup struct {
+	Winner           string `json:"winner"`
+	Loser            string `json:"loser"`
+	Upsets           int    `json:"upsets"`
+	TotalUpsetFactor int    `json:"totalUpsetFactor"`
+}
+
+// CharacterReport has the character statistics of one or more events.
+type CharacterReport struct {
+	Title  string         `json:"title"`
+	Events []EventSummary `json:"events"`
+	// Characters are sorted by upsets won.
+	Characters []CharacterStats `json:"characters"`
+	// Matchups has the non-empty cells of the upset matrix, most upsets
+	// first.
+	Matchups []CharacterMatchup `json:"matchups"`
+}
+
+// splitCharacters returns the characters of an entrant as listed by
+// Set.GetCharacterSelections.
+func splitCharacters(characters string) []string {
+	var res []string
+	for _, character := range strings.Split(characters, ", ") {
+		if character = strings.TrimSpace(character); character != "" {
+			res = append(res, character)
+		}
+	}
+	return res
+}
+
+// playedSets returns the completed sets of the thread with a score, once
+// each whichever sections they are in. DQs and forfeits are left out.
+func playedSets(upsetThread *UpsetThread) []UpsetThreadItem {
+	var res []UpsetThreadItem
+	seen := make(map[string]bool)
+	for _, items := range [][]UpsetThreadItem{upsetThread.GrandFinals, upsetThread.Winners, upsetThread.Losers, upsetThread.Pools, upsetThread.Notables, upsetThread.DQs, upsetThread.Other} {
+		for _, item := range items {
+			if seen[item.Id] || !IsRateable(item) {
+				continue
+			}
+			seen[item.Id] = true
+			res = append(res, item)
+		}
+	}
+	return res
+}
+
+// NewCharacterReport counts the upsets won and lost by each character in the
+// upset threads, and the upsets between each pair of characters.
+func NewCharacterReport(title string, upsetThreads []*UpsetThread) *CharacterReport {
+	res := &CharacterReport{Title: title, Events: []EventSummary{}, Characters: []CharacterStats{}, Matchups: []CharacterMatchup{}}
+	characters := make(map[string]*CharacterStats)
+	matchups := make(map[[2]string]*CharacterMatchup)
+	stats := func(character string) *CharacterStats {
+		if _, ok := characters[character]; !ok {
+			characters[character] = &CharacterStats{Character: character}
+		}
+		return characters[character]
+	}
+	for _, upsetThread := range upsetThreads {
+		res.Events = append(res.Events, EventSummary{Slug: upsetThread.Slug, Title: cmp.Or(upsetThread.Title, upsetThread.Slug)})
+		for _, item := range playedSets(upsetThread) {
+			isUpset := isRankedUpset(item)
+			winners, losers := splitCharacters(item.WinnersCharacters), splitCharacters(item.LosersCharacters)
+			for _, character := range winners {
+				s := stats(character)
+				s.Sets++
+				if item.Notable {
+					s.notables++
+				}
+				if isUpset {
+					s.UpsetsWon++
+					s.totalUpsetFactor += item.UpsetFactor
+				}
+			}
+			for _, character := range losers {
+				s := stats(character)
+				s.Sets++
+				if item.Notable {
+					s.notables++
+				}
+				if isUpset {
+					s.UpsetsLost++
+				}
+			}
+			if !isUpset {
+				continue
+			}
+			for _, winner := range winners {
+				for _, loser := range losers {
+					key := [2]string{winner, loser}
+					if _, ok := matchups[key]; !ok {
+						matchups[key] = &CharacterMatchup{Winner: winner, Loser: loser}
+					}
+					matchups[key].Upsets++
+					matchups[key].TotalUpsetFactor += item.UpsetFactor
+				}
+			}
+		}
+	}
+	for _, s := range characters {
+		if s.UpsetsWon > 0 {
+			s.AverageUpsetFactor = float64(s.totalUpsetFactor) / float64(s.UpsetsWon)
+		}
+		s.NotableRate = float64(s.notables) / float64(s.Sets)
+		res.Characters = append(res.Characters, *s)
+	}
+	slices.SortFunc(res.Characters, func(i, j CharacterStats) int {
+		return cmp.Or(
+			cmp.Compare(j.UpsetsWon, i.UpsetsWon),
+			cmp.Compare(j.AverageUpsetFactor, i.AverageUpsetFactor),
+			cmp.Compare(i.UpsetsLost, j.UpsetsLost),
+			strings.Compare(i.Character, j.Character),
+		)
+	})
+	for _, matchup := range matchups {
+		res.Matchups = append(res.Matchups, *matchup)
+	}
+	slices.SortFunc(res.Matchups, func(i, j CharacterMatchup) int {
+		return cmp.Or(
+			cmp.Compare(j.Upsets, i.Upsets),
+			cmp.Compare(j.TotalUpsetFactor, i.TotalUpsetFactor),
+			strings.Compare(i.Winner, j.Winner),
+			strings.Compare(i.Loser, j.Loser),
+		)
+	})
+	return res
+}
@@ -0,0 +1,41 @@
+package domain
+
+import "testing"
+
+func TestNewCharacterReport(t *testing.T) {
+	score := "3-1"
+	upsetThread := &UpsetThread{
+		Slug:  "tournament/a/event/singles",
+		Title: "A",
+		Winners: []UpsetThreadItem{
+			{Id: "1", WinnersCharacters: "Steve", LosersCharacters: "Cloud, Sephiroth", Score: &score, UpsetFactor: 6, CompletedAt: 10},
+			{Id: "2", WinnersCharacters: "Steve", LosersCharacters: "Cloud", Score: &score, UpsetFactor: 2, CompletedAt: 20, Notable: true},
+			{Id: "3", WinnersCharacters: "Steve", LosersCharacters: "Kazuya", Score: &score, UpsetFactor: 4, CompletedAt: 30, Outcome: OutcomeDQ},
+		},
+		Notables: []UpsetThreadItem{
+			{Id: "4", WinnersCharacters: "Cloud", LosersCharacters: "Steve", Score: &score, CompletedAt: 40, Notable: true},
+		},
+		Other: []UpsetThreadItem{
+			{Id: "5", WinnersCharacters: "Kazuya", LosersCharacters: "Cloud", Score: &score, CompletedAt: 50},
+		},
+		Watched: []UpsetThreadItem{
+			{Id: "1", WinnersCharacters: "Steve", LosersCharacters: "Cloud, Sephiroth", Score: &score, UpsetFactor: 6, CompletedAt: 10},
+		},
+	}
+	res := NewCharacterReport("A", []*UpsetThread{upsetThread})
+	if len(res.Characters) != 4 {
+		t.Fatalf("Expected 4 characters, got %v", res.Characters)
+	}
+	steve := res.Characters[0]
+	if steve.Character != "Steve" || steve.Sets != 3 || steve.UpsetsWon != 2 || steve.AverageUpsetFactor != 4 || steve.NotableRate != 2.0/3 {
+		t.Errorf("Expected Steve with 3 sets, 2 upsets won at 4 on average and 2 notable sets, got %+v", steve)
+	}
+	for _, stats := range res.Characters {
+		if stats.Character == "Cloud" && (stats.Sets != 4 || stats.UpsetsLost != 2 || stats.UpsetsWon != 0) {
+			t.Errorf("Expected Cloud with 4 sets and 2 upsets lost, got %+v", stats)
+		}
+	}
+	if len(res.Matchups) != 2 || res.Matchups[0] != (CharacterMatchup{Winner: "Steve", Loser: "Cloud", Upsets: 2, TotalUpsetFactor: 8}) || res.Matchups[1].Loser != "Sephiroth" {
+		t.Errorf("Expected Steve over Cloud twice, then over Sephiroth, got %v", res.Matchups)
+	}
+}
@@ -0,0 +1,28 @@
+package domain
+
+type Event struct {
+	Slug          string   `json:"slug"`
+	Title         string   `json:"title"`
+	Subreddit     string   `json:"subreddit"`
+	File          string   `json:"file"`
+	GameSlug      string   `json:"gameSlug"`
+	Timezone      string   `json:"timezone"`
+	TimeFormat    string   `json:"timeFormat"`
+	Watchlists    []string `json:"watchlists"`
+	Paused        bool     `json:"paused"`
+	LastError     string   `json:"lastError"`
+	LastErrorAt   int      `json:"lastErrorAt"`
+	LastUpdatedAt int      `json:"lastUpdatedAt"`
+}
+
+// ApplyTo overrides the upset thread's display settings with the ones
+// configured for the event and stamps it with the event's last poll.
+func (e *Event) ApplyTo(upsetThread *UpsetThread) {
+	upsetThread.LastUpdatedAt = e.LastUpdatedAt
+	if e.Timezone != "" {
+		upsetThread.Timezone = e.Timezone
+	}
+	if e.TimeFormat != "" {
+		upsetThread.TimeFormat = e.TimeFormat
+	}
+}
@@ -0,0 +1,36 @@
+package domain
+
+import "encoding/xml"
+
+// Feed is an Atom feed, see RFC 4287.
+type Feed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Id      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  FeedAuthor  `xml:"author"`
+	Links   []FeedLink  `xml:"link"`
+	Entries []FeedEntry `xml:"entry"`
+}
+
+type FeedAuthor struct {
+	Name string `xml:"name"`
+}
+
+type FeedLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type FeedCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type FeedEntry struct {
+	Id       string       `xml:"id"`
+	Title    string       `xml:"title"`
+	Updated  string       `xml:"updated"`
+	Link     FeedLink     `xml:"link"`
+	Category FeedCategory `xml:"category"`
+	Summary  string       `xml:"summary"`
+}
@@ -0,0 +1,125 @@
+package domain
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// GrandFinal is what a grand final set meant for the bracket, worded to
+// follow the winner's name.
+type GrandFinal string
+
+const (
+	// GrandFinalWon is the winners side entrant taking the first set.
+	GrandFinalWon GrandFinal = "won grand finals"
+	// GrandFinalReset is the losers side entrant taking the first set.
+	GrandFinalReset GrandFinal = "reset the bracket"
+	// GrandFinalFromLosers is the losers side entrant taking the reset.
+	GrandFinalFromLosers GrandFinal = "won from losers"
+	// GrandFinalResetWon is the winners side entrant taking the reset.
+	GrandFinalResetWon GrandFinal = "won the bracket reset"
+)
+
+func (s *Set) isGrandFinalRound() bool {
+	if s.FullRoundText == nil || s.Round <= 0 {
+		return false
+	}
+	text := strings.ToLower(*s.FullRoundText)
+	return strings.Contains(text, "grand final") || strings.Contains(text, "true final")
+}
+
+func (s *Set) isResetRound() bool {
+	text := strings.ToLower(*s.FullRoundText)
+	return strings.Contains(text, "reset") || strings.Contains(text, "true final")
+}
+
+// wonBy reports whether the set was won by any of the entrants.
+func (s *Set) wonBy(entrantIds ...int) bool {
+	return slices.Contains(entrantIds, s.Winner.Id)
+}
+
+func (s *Set) isBetween(other *Set) bool {
+	return (s.Winner.Id == other.Winner.Id && s.Loser.Id == other.Loser.Id) ||
+		(s.Winner.Id == other.Loser.Id && s.Loser.Id == other.Winner.Id)
+}
+
+// MarkFinalPhase sets FinalPhase on the sets of the phase finalPhaseId. When
+// the phases of the event are not known, such as for sets read from a file,
+// sets of a phase played as a single bracket are taken to be in the final
+// phase.
+func MarkFinalPhase(sets []Set, finalPhaseId int) {
+	for i := range sets {
+		if finalPhaseId != 0 {
+			sets[i].FinalPhase = sets[i].PhaseId == finalPhaseId
+		} else {
+			sets[i].FinalPhase = sets[i].PoolIdentifier == ""
+		}
+	}
+}
+
+// isEventGrandFinal reports whether the set is named as a grand final in the
+// event's final bracket, leaving out the grand finals of pools.
+func (s *Set) isEventGrandFinal() bool {
+	return s.FinalPhase && !s.IsPoolStage() && s.isGrandFinalRound()
+}
+
+// MarkGrandFinals sets GrandFinal on the grand final and bracket reset of the
+// event. They are the sets of the final phase named as grand finals between
+// the entrants of the last one played, so that the grand finals of pools are
+// left alone while the final phase is not played yet. The reset is named as
+// such or is the later round. The losers side entrant is the one who won the
+// last losers bracket set.
+func MarkGrandFinals(sets []Set) {
+	last := -1
+	for i := range sets {
+		if sets[i].isEventGrandFinal() && (last < 0 || sets[i].CompletedAt > sets[last].CompletedAt) {
+			last = i
+		}
+	}
+	if last < 0 {
+		return
+	}
+	var finals []*Set
+	for i := range sets {
+		if sets[i].isEventGrandFinal() && sets[i].isBetween(&sets[last]) {
+			finals = append(finals, &sets[i])
+		}
+	}
+	slices.SortFunc(finals, func(i, j *Set) int {
+		if i.isResetRound() != j.isResetRound() {
+			if i.isResetRound() {
+				return 1
+			}
+			return -1
+		}
+		return cmp.Or(cmp.Compare(i.Round, j.Round), cmp.Compare(i.CompletedAt, j.CompletedAt))
+	})
+	losersSide, losersFinal := 0, -1
+	for i := range sets {
+		if !sets[i].IsLosersBracket() || !sets[i].wonBy(sets[last].Winner.Id, sets[last].Loser.Id) {
+			continue
+		}
+		if losersFinal < 0 || sets[i].CompletedAt > sets[losersFinal].CompletedAt {
+			losersSide, losersFinal = sets[i].Winner.Id, i
+		}
+	}
+	// A reset only happens when the losers side entrant took the first set.
+	if len(finals) > 1 && losersSide == 0 && !finals[0].isResetRound() {
+		losersSide = finals[0].Winner.Id
+	}
+	for i, set := range finals[:min(len(finals), 2)] {
+		fromLosers := set.Winner.Id == losersSide
+		isReset := i > 0 || set.isResetRound()
+		switch {
+		case !isReset && fromLosers:
+			set.GrandFinal = GrandFinalReset
+		case !isReset:
+			set.GrandFinal = GrandFinalWon
+		case fromLosers:
+			set.GrandFinal = GrandFinalFromLosers
+		default:
+			set.GrandFinal = GrandFinalResetWon
+		}
+	}
+}
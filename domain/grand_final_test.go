@@ -0,0 +1,95 @@
+package domain
+
+import "testing"
+
+func grandFinalTestSet(id string, round int, fullRoundText string, winner, loser int, completedAt int) Set {
+	return Set{
+		Id:            id,
+		Round:         round,
+		FullRoundText: &fullRoundText,
+		Winner:        Entrant{Id: winner},
+		Loser:         Entrant{Id: loser},
+		CompletedAt:   completedAt,
+		BracketType:   BracketTypeDoubleElimination,
+		PhaseId:       2,
+		FinalPhase:    true,
+	}
+}
+
+// poolGrandFinalTestSet is a set of a double elimination pool of the first phase.
+func poolGrandFinalTestSet(id string, round int, fullRoundText string, winner, loser int, completedAt int) Set {
+	set := grandFinalTestSet(id, round, fullRoundText, winner, loser, completedAt)
+	set.PhaseId, set.PoolIdentifier, set.FinalPhase = 1, "A1", false
+	return set
+}
+
+func TestMarkGrandFinals(t *testing.T) {
+	// 1 comes from winners and 2 from losers. 3 and 4 met in a pool's grand
+	// final.
+	pool := poolGrandFinalTestSet("pool", 3, "Grand Final", 3, 4, 1)
+	winnersFinal := grandFinalTestSet("wf", 3, "Winners Final", 1, 2, 2)
+	losersFinal := grandFinalTestSet("lf", -4, "Losers Final", 2, 5, 3)
+	tests := []struct {
+		name     string
+		sets     []Set
+		expected map[string]GrandFinal
+	}{
+		{
+			"winners side wins",
+			[]Set{pool, winnersFinal, losersFinal, grandFinalTestSet("gf", 4, "Grand Final", 1, 2, 4)},
+			map[string]GrandFinal{"gf": GrandFinalWon},
+		},
+		{
+			"losers side wins from losers",
+			[]Set{pool, winnersFinal, losersFinal, grandFinalTestSet("reset", 5, "Grand Final Reset", 2, 1, 5), grandFinalTestSet("gf", 4, "Grand Final", 2, 1, 4)},
+			map[string]GrandFinal{"gf": GrandFinalReset, "reset": GrandFinalFromLosers},
+		},
+		{
+			"winners side wins the reset",
+			[]Set{winnersFinal, losersFinal, grandFinalTestSet("gf", 4, "Grand Final", 2, 1, 4), grandFinalTestSet("reset", 5, "Grand Final Reset", 1, 2, 5)},
+			map[string]GrandFinal{"gf": GrandFinalReset, "reset": GrandFinalResetWon},
+		},
+		{
+			"reset named like the grand final",
+			[]Set{grandFinalTestSet("gf", 4, "Grand Final", 2, 1, 4), grandFinalTestSet("reset", 5, "Grand Final", 2, 1, 5)},
+			map[string]GrandFinal{"gf": GrandFinalReset, "reset": GrandFinalFromLosers},
+		},
+		{
+			"reset without the grand final",
+			[]Set{losersFinal, grandFinalTestSet("reset", 5, "True Finals", 2, 1, 5)},
+			map[string]GrandFinal{"reset": GrandFinalFromLosers},
+		},
+		{
+			"only a pool's grand final and reset played so far",
+			[]Set{poolGrandFinalTestSet("pool-wf", 3, "Winners Final", 3, 4, 1), pool, poolGrandFinalTestSet("pool-reset", 4, "Grand Final Reset", 3, 4, 2)},
+			map[string]GrandFinal{},
+		},
+		{
+			"no grand finals",
+			[]Set{winnersFinal, losersFinal},
+			map[string]GrandFinal{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			MarkGrandFinals(test.sets)
+			for _, set := range test.sets {
+				if set.GrandFinal != test.expected[set.Id] {
+					t.Errorf("Expected %s to be %q, got %q", set.Id, test.expected[set.Id], set.GrandFinal)
+				}
+			}
+		})
+	}
+}
+
+func TestMarkFinalPhase(t *testing.T) {
+	sets := []Set{{Id: "pool", PhaseId: 1, PoolIdentifier: "A1"}, {Id: "top8", PhaseId: 2}, {Id: "top64", PhaseId: 3}}
+	MarkFinalPhase(sets, 2)
+	if sets[0].FinalPhase || !sets[1].FinalPhase || sets[2].FinalPhase {
+		t.Errorf("Expected only the sets of the final phase, got %+v", sets)
+	}
+	MarkFinalPhase(sets, 0)
+	if sets[0].FinalPhase || !sets[1].FinalPhase || !sets[2].FinalPhase {
+		t.Errorf("Expected the sets of single bracket phases without known phases, got %+v", sets)
+	}
+}
@@ -0,0 +1,122 @@
+package domain
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+)
+
+// HeadToHead is the winner's record against the loser before a set.
+type HeadToHead struct {
+	Wins   int `json:"wins"`
+	Losses int `json:"losses"`
+}
+
+// FirstWin reports whether the set was the winner's first win over the
+// loser.
+func (h *HeadToHead) FirstWin() bool {
+	return h != nil && h.Wins == 0
+}
+
+// HeadToHeadSet is a set between two players, kept in their head-to-head
+// history.
+type HeadToHeadSet struct {
+	Event       string `json:"event"`
+	SetId       string `json:"setId"`
+	Winner      string `json:"winner"`
+	WinnersName string `json:"winnersName"`
+	Score       string `json:"score"`
+	LosersName  string `json:"losersName"`
+	CompletedAt int    `json:"completedAt"`
+}
+
+// HeadToHeadKey identifies the history of two players, whichever order they
+// are given in. Players are keyed as by UpsetThreadItem.WinnersKey.
+func HeadToHeadKey(player, opponent string) string {
+	if opponent < player {
+		player, opponent = opponent, player
+	}
+	return player + " vs " + opponent
+}
+
+// NewHeadToHead returns the winner's record against the loser from the sets
+// of their history completed before the item.
+func NewHeadToHead(history []HeadToHeadSet, event string, item UpsetThreadItem) *HeadToHead {
+	res := &HeadToHead{}
+	winner := item.WinnersKey()
+	for _, set := range history {
+		if set.CompletedAt >= item.CompletedAt || (set.Event == event && set.SetId == item.Id) {
+			continue
+		}
+		if set.Winner == winner {
+			res.Wins++
+		} else {
+			res.Losses++
+		}
+	}
+	return res
+}
+
+// AddHeadToHeadSet adds the item to the history of its players unless it is
+// there already, keeping the history in the order sets were completed. Ties
+// are left out, as neither player won.
+func AddHeadToHeadSet(history []HeadToHeadSet, event string, item UpsetThreadItem) ([]HeadToHeadSet, bool) {
+	if item.Outcome == OutcomeTie || slices.ContainsFunc(history, func(set HeadToHeadSet) bool { return set.Event == event && set.SetId == item.Id }) {
+		return history, false
+	}
+	history = append(history, HeadToHeadSet{
+		Event:       event,
+		SetId:       item.Id,
+		Winner:      item.WinnersKey(),
+		WinnersName: item.WinnersName,
+		Score:       scoreOf(item),
+		LosersName:  item.LosersName,
+		CompletedAt: item.CompletedAt,
+	})
+	slices.SortStableFunc(history, func(i, j HeadToHeadSet) int {
+		return cmp.Compare(i.CompletedAt, j.CompletedAt)
+	})
+	return history, true
+}
+
+// HeadToHeadNote describes the winner's record against the loser before the
+// set, such as "first win after 3 losses", or is empty when it is not known.
+func (i UpsetThreadItem) HeadToHeadNote() string {
+	h := i.HeadToHead
+	switch {
+	case h == nil:
+		return ""
+	case h.Wins == 0 && h.Losses == 0:
+		return "first meeting"
+	case h.Wins == 0 && h.Losses == 1:
+		return "first win after 1 loss"
+	case h.Wins == 0:
+		return fmt.Sprintf("first win after %d losses", h.Losses)
+	}
+	return fmt.Sprintf("previously %d-%d", h.Wins, h.Losses)
+}
+
+// PlayerHeadToHead is the history of two players, with the record of the
+// first against the second.
+type PlayerHeadToHead struct {
+	Player   string          `json:"player"`
+	Opponent string          `json:"opponent"`
+	Wins     int             `json:"wins"`
+	Losses   int             `json:"losses"`
+	Sets     []HeadToHeadSet `json:"sets"`
+}
+
+func NewPlayerHeadToHead(player, opponent string, history []HeadToHeadSet) *PlayerHeadToHead {
+	res := &PlayerHeadToHead{Player: player, Opponent: opponent, Sets: history}
+	if res.Sets == nil {
+		res.Sets = []HeadToHeadSet{}
+	}
+	for _, set := range history {
+		if set.Winner == player {
+			res.Wins++
+		} else {
+			res.Losses++
+		}
+	}
+	return res
+}
@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestHeadToHeadKey(t *testing.T) {
+	if HeadToHeadKey("sonix", "1004") != HeadToHeadKey("1004", "sonix") {
+		t.Errorf("Expected the key not to depend on the order of the players")
+	}
+}
+
+func TestNewHeadToHead(t *testing.T) {
+	score := "3-1"
+	event := "tournament/b/event/singles"
+	sparg0 := UpsetThreadItem{Id: "10", WinnersName: "Sparg0", WinnersPlayerIds: []int{1}, LosersName: "Sonix", LosersPlayerIds: []int{2}, Score: &score, CompletedAt: 50}
+	sonix := UpsetThreadItem{Id: "1", WinnersName: "Sonix", WinnersPlayerIds: []int{2}, LosersName: "Sparg0", LosersPlayerIds: []int{1}, Score: &score}
+
+	var history []HeadToHeadSet
+	for i, completedAt := range []int{10, 20} {
+		sonix.Id, sonix.CompletedAt = strconv.Itoa(i+1), completedAt
+		history, _ = AddHeadToHeadSet(history, "tournament/a/event/singles", sonix)
+	}
+	history, _ = AddHeadToHeadSet(history, event, sparg0)
+	if _, added := AddHeadToHeadSet(history, event, sparg0); added {
+		t.Errorf("Expected a set to be added once")
+	}
+	tie := sparg0
+	tie.Id, tie.CompletedAt, tie.Outcome = "9", 30, OutcomeTie
+	if _, added := AddHeadToHeadSet(history, event, tie); added {
+		t.Errorf("Expected ties to be left out")
+	}
+	later := sparg0
+	later.Id, later.CompletedAt = "11", 60
+	history, _ = AddHeadToHeadSet(history, event, later)
+
+	res := NewHeadToHead(history, event, sparg0)
+	if res.Wins != 0 || res.Losses != 2 || !res.FirstWin() {
+		t.Errorf("Expected a first win after 2 losses, got %+v", res)
+	}
+	if res := NewHeadToHead(history, event, later); res.Wins != 1 || res.Losses != 2 || res.FirstWin() {
+		t.Errorf("Expected a 1-2 record before the later set, got %+v", res)
+	}
+	if history[0].CompletedAt != 10 || history[3].SetId != "11" || history[2].Winner != "1" {
+		t.Errorf("Expected the history in the order sets were completed, got %+v", history)
+	}
+
+	record := NewPlayerHeadToHead("1", "2", history)
+	if record.Wins != 2 || record.Losses != 2 || len(record.Sets) != 4 {
+		t.Errorf("Expected a 2-2 record, got %+v", record)
+	}
+}
+
+func TestHeadToHeadNote(t *testing.T) {
+	tests := []struct {
+		headToHead *HeadToHead
+		expected   string
+	}{
+		{nil, ""},
+		{&HeadToHead{}, "first meeting"},
+		{&HeadToHead{Losses: 1}, "first win after 1 loss"},
+		{&HeadToHead{Losses: 4}, "first win after 4 losses"},
+		{&HeadToHead{Wins: 3, Losses: 1}, "previously 3-1"},
+	}
+	for _, test := range tests {
+		if res := (UpsetThreadItem{HeadToHead: test.headToHead}).HeadToHeadNote(); res != test.expected {
+			t.Errorf("Expected %q, got %q", test.expected, res)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package domain
+
+import "strings"
+
+// SponsorSeparator separates the sponsor prefix from the gamer tag in
+// start.gg entrant names, e.g. "FaZe | Sparg0".
+const SponsorSeparator = " | "
+
+// NormaliseName trims the name and collapses runs of whitespace.
+func NormaliseName(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// SplitName splits an entrant name into its sponsor prefix and gamer tag at
+// the last separator. It is only used when start.gg did not report the
+// participant's prefix and gamer tag, such as for sets stored by older
+// versions.
+func SplitName(name string) (string, string) {
+	name = NormaliseName(name)
+	i := strings.LastIndex(name, SponsorSeparator)
+	if i < 0 {
+		return "", name
+	}
+	return name[:i], name[i+len(SponsorSeparator):]
+}
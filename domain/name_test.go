@@ -0,0 +1,26 @@
+package domain
+
+import "testing"
+
+type splitNameTestCase struct {
+	name, expectedPrefix, expectedTag string
+}
+
+var splitNameTestCases = []splitNameTestCase{
+	{"FaZe | Sparg0", "FaZe", "Sparg0"},
+	{"Sonix", "", "Sonix"},
+	{"ST|BLZ | MrLasagna", "ST|BLZ", "MrLasagna"},
+	{"SC | Finos | Rugar", "SC | Finos", "Rugar"},
+	{"  LG |  Tweek ", "LG", "Tweek"},
+}
+
+func TestSplitName(t *testing.T) {
+	for _, tc := range splitNameTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			prefix, tag := SplitName(tc.name)
+			if prefix != tc.expectedPrefix || tag != tc.expectedTag {
+				t.Errorf("Expected %q and %q, got %q and %q", tc.expectedPrefix, tc.expectedTag, prefix, tag)
+			}
+		})
+	}
+}
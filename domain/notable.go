@@ -0,0 +1,138 @@
+package domain
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Values of start.gg's setGamesType. A best of set ends once an entrant has
+// won most of TotalGames, while every game of a total games set is played.
+const (
+	GamesTypeBestOf     = 1
+	GamesTypeTotalGames = 2
+)
+
+// NotableRules decide which sets the favourite won are notable. A set is
+// notable when it went to the last possible game.
+type NotableRules struct {
+	// ReverseSweeps keeps sets the winner won after losing every game up to
+	// match point.
+	ReverseSweeps bool
+	// LastGameMargin, when set, also requires the last game to have been won
+	// by at most this many stocks or points. Sets without game scores are kept.
+	LastGameMargin int
+}
+
+var DefaultNotableRules = NotableRules{ReverseSweeps: true}
+
+// DefaultRuleset is the key of the rules used for games without their own.
+const DefaultRuleset = "default"
+
+// Rulesets are the notable rules of each game, keyed by its start.gg slug
+// such as game/ultimate.
+type Rulesets map[string]NotableRules
+
+func (r Rulesets) For(gameSlug string) NotableRules {
+	if rules, ok := r[gameSlug]; ok {
+		return rules
+	}
+	if rules, ok := r[DefaultRuleset]; ok {
+		return rules
+	}
+	return DefaultNotableRules
+}
+
+// gameScores returns the games won by the winner and the loser.
+func (s *Set) gameScores() (int, int, bool) {
+	if s.Outcome != OutcomeScore || s.Score == nil {
+		return 0, 0, false
+	}
+	winnerScore, loserScore, _ := strings.Cut(*s.Score, "-")
+	w, err := strconv.Atoi(winnerScore)
+	if err != nil {
+		return 0, 0, false
+	}
+	l, err := strconv.Atoi(loserScore)
+	if err != nil {
+		return 0, 0, false
+	}
+	return w, l, true
+}
+
+// WentToLastGame reports whether the set was decided by its last possible
+// game, such as 2-1 in a best of 3, 3-2 in a best of 5 or 5-4 in a first to
+// 5. When TotalGames does not fit the score, the winner must have won by a
+// single game.
+func (s *Set) WentToLastGame() bool {
+	w, l, ok := s.gameScores()
+	if !ok || l == 0 {
+		return false
+	}
+	if s.GamesType == GamesTypeBestOf && s.TotalGames%2 == 1 && w+l <= s.TotalGames {
+		return w+l == s.TotalGames
+	}
+	return w == l+1
+}
+
+// IsReverseSweep reports whether the winner lost every game until the loser
+// was at match point, such as going from 0-2 to 3-2. Best of 3 sets are not
+// reverse sweeps.
+func (s *Set) IsReverseSweep() bool {
+	w, l, ok := s.gameScores()
+	if !ok || w < 3 || l != w-1 || s.Games == nil {
+		return false
+	}
+	var played []Game
+	for _, game := range *s.Games {
+		if game.WinnerId != 0 {
+			played = append(played, game)
+		}
+	}
+	if len(played) != w+l {
+		return false
+	}
+	for i, game := range played {
+		if (i < l) == (game.WinnerId == s.Winner.Id) {
+			return false
+		}
+	}
+	return true
+}
+
+// lastGameMargin returns how much the last game was won by, when start.gg
+// reports game scores.
+func (s *Set) lastGameMargin() (int, bool) {
+	if s.Games == nil {
+		return 0, false
+	}
+	for i := len(*s.Games) - 1; i >= 0; i-- {
+		game := (*s.Games)[i]
+		if game.WinnerId == 0 {
+			continue
+		}
+		winnerScore, ok := game.Scores[s.Winner.Id]
+		if !ok {
+			return 0, false
+		}
+		loserScore, ok := game.Scores[s.Loser.Id]
+		if !ok {
+			return 0, false
+		}
+		return winnerScore - loserScore, true
+	}
+	return 0, false
+}
+
+// IsNotable reports whether the set is notable under the rules.
+func (s *Set) IsNotable(rules NotableRules) bool {
+	if !s.WentToLastGame() {
+		return false
+	}
+	if !rules.ReverseSweeps && s.IsReverseSweep() {
+		return false
+	}
+	if margin, ok := s.lastGameMargin(); ok && rules.LastGameMargin > 0 && margin > rules.LastGameMargin {
+		return false
+	}
+	return true
+}
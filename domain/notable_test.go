@@ -0,0 +1,63 @@
+package domain
+
+import "testing"
+
+func notableSet(score string, totalGames, gamesType int, games *[]Game) *Set {
+	return &Set{
+		Score:      &score,
+		Outcome:    ScoreOutcome(score),
+		TotalGames: totalGames,
+		GamesType:  gamesType,
+		Games:      games,
+		Winner:     Entrant{Id: 1},
+		Loser:      Entrant{Id: 2},
+	}
+}
+
+func TestIsNotable(t *testing.T) {
+	reverseSweep := &[]Game{{Id: 1, WinnerId: 2}, {Id: 2, WinnerId: 2}, {Id: 3, WinnerId: 1}, {Id: 4, WinnerId: 1}, {Id: 5, WinnerId: 1}}
+	lastStock := &[]Game{{Id: 1, WinnerId: 1, Scores: map[int]int{1: 3, 2: 0}}, {Id: 2, WinnerId: 2, Scores: map[int]int{1: 0, 2: 2}}, {Id: 3, WinnerId: 1, Scores: map[int]int{1: 1, 2: 0}}}
+	threeStock := &[]Game{{Id: 1, WinnerId: 1, Scores: map[int]int{1: 1, 2: 0}}, {Id: 2, WinnerId: 2, Scores: map[int]int{1: 0, 2: 2}}, {Id: 3, WinnerId: 1, Scores: map[int]int{1: 3, 2: 0}}}
+	tests := []struct {
+		name     string
+		set      *Set
+		rules    NotableRules
+		expected bool
+	}{
+		{"last game of best of 3", notableSet("2-1", 3, GamesTypeBestOf, nil), DefaultNotableRules, true},
+		{"not the last game of best of 5", notableSet("2-1", 5, GamesTypeBestOf, nil), DefaultNotableRules, false},
+		{"last game of best of 5", notableSet("3-2", 5, GamesTypeBestOf, nil), DefaultNotableRules, true},
+		{"last game of first to 5", notableSet("5-4", 9, GamesTypeBestOf, nil), DefaultNotableRules, true},
+		{"last game of first to 4", notableSet("4-3", 7, GamesTypeBestOf, nil), DefaultNotableRules, true},
+		{"sweep", notableSet("3-0", 5, GamesTypeBestOf, nil), DefaultNotableRules, false},
+		{"longer than total games", notableSet("3-2", 3, GamesTypeBestOf, nil), DefaultNotableRules, true},
+		{"total games", notableSet("2-1", 3, GamesTypeTotalGames, nil), DefaultNotableRules, true},
+		{"unknown length", notableSet("6-5", 0, 0, nil), DefaultNotableRules, true},
+		{"DQ", notableSet("DQ", 3, GamesTypeBestOf, nil), DefaultNotableRules, false},
+		{"reverse sweep", notableSet("3-2", 5, GamesTypeBestOf, reverseSweep), DefaultNotableRules, true},
+		{"reverse sweep not counted", notableSet("3-2", 5, GamesTypeBestOf, reverseSweep), NotableRules{}, false},
+		{"last stock", notableSet("2-1", 3, GamesTypeBestOf, lastStock), NotableRules{LastGameMargin: 1}, true},
+		{"last game not close", notableSet("2-1", 3, GamesTypeBestOf, threeStock), NotableRules{LastGameMargin: 1}, false},
+		{"last game without scores", notableSet("2-1", 3, GamesTypeBestOf, &[]Game{{Id: 1, WinnerId: 1}, {Id: 2, WinnerId: 2}, {Id: 3, WinnerId: 1}}), NotableRules{LastGameMargin: 1}, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if res := test.set.IsNotable(test.rules); res != test.expected {
+				t.Errorf("Expected %t, got %t", test.expected, res)
+			}
+		})
+	}
+}
+
+func TestRulesetsFor(t *testing.T) {
+	melee := NotableRules{LastGameMargin: 1}
+	if rules := (Rulesets{"game/melee": melee}).For("game/melee"); rules != melee {
+		t.Errorf("Expected the game's rules, got %+v", rules)
+	}
+	if rules := (Rulesets{"game/melee": melee}).For("game/ultimate"); rules != DefaultNotableRules {
+		t.Errorf("Expected the default rules, got %+v", rules)
+	}
+	if rules := (Rulesets{DefaultRuleset: melee}).For("game/ultimate"); rules != melee {
+		t.Errorf("Expected the configured default rules, got %+v", rules)
+	}
+}
@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"strconv"
+	"strings"
+)
+
+// BracketType is the format of the phase group a set was played in, as named
+// by start.gg.
+type BracketType string
+
+const (
+	BracketTypeSingleElimination BracketType = "SINGLE_ELIMINATION"
+	BracketTypeDoubleElimination BracketType = "DOUBLE_ELIMINATION"
+	BracketTypeRoundRobin        BracketType = "ROUND_ROBIN"
+	BracketTypeSwiss             BracketType = "SWISS"
+)
+
+// IsPoolStage reports whether entrants play a number of sets in the phase
+// group instead of being knocked out.
+func (b BracketType) IsPoolStage() bool {
+	return b == BracketTypeRoundRobin || b == BracketTypeSwiss
+}
+
+// StageName names the pool stage in upset lines, such as "went 1-4 in
+// pools".
+func (b BracketType) StageName() string {
+	if b == BracketTypeSwiss {
+		return "Swiss"
+	}
+	return "pools"
+}
+
+type poolEntrant struct {
+	phaseGroupId, entrantId int
+}
+
+type poolRecord struct {
+	wins, losses, ties int
+}
+
+func (r poolRecord) String() string {
+	res := strconv.Itoa(r.wins) + "-" + strconv.Itoa(r.losses)
+	if r.ties > 0 {
+		res += "-" + strconv.Itoa(r.ties)
+	}
+	return res
+}
+
+// MarkPoolRecords sets the record of both entrants in their pool on every
+// pool stage set, counting the completed sets of the pool.
+func MarkPoolRecords(sets []Set) {
+	records := make(map[poolEntrant]*poolRecord)
+	record := func(phaseGroupId, entrantId int) *poolRecord {
+		key := poolEntrant{phaseGroupId, entrantId}
+		if _, ok := records[key]; !ok {
+			records[key] = &poolRecord{}
+		}
+		return records[key]
+	}
+	for _, set := range sets {
+		if !set.IsPoolStage() || set.CompletedAt == 0 {
+			continue
+		}
+		winner, loser := record(set.PhaseGroupId, set.Winner.Id), record(set.PhaseGroupId, set.Loser.Id)
+		if set.Outcome == OutcomeTie {
+			winner.ties++
+			loser.ties++
+		} else {
+			winner.wins++
+			loser.losses++
+		}
+	}
+	for i := range sets {
+		if !sets[i].IsPoolStage() || sets[i].CompletedAt == 0 {
+			continue
+		}
+		sets[i].Winner.Record = record(sets[i].PhaseGroupId, sets[i].Winner.Id).String()
+		sets[i].Loser.Record = record(sets[i].PhaseGroupId, sets[i].Loser.Id).String()
+	}
+}
+
+// PhaseLabel names the phase and pool a set was played in, such as "Top 64"
+// or "Pools A12". Either can be empty.
+func PhaseLabel(phaseName, poolIdentifier string) string {
+	return strings.TrimSpace(phaseName + " " + poolIdentifier)
+}
+
+// InPhases reports whether the phase is one of phases, ignoring case. Every
+// phase is in an empty list.
+func InPhases(phaseName string, phases []string) bool {
+	if len(phases) == 0 {
+		return true
+	}
+	for _, phase := range phases {
+		if strings.EqualFold(strings.TrimSpace(phase), phaseName) {
+			return true
+		}
+	}
+	return false
+}
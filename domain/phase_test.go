@@ -0,0 +1,53 @@
+package domain
+
+import "testing"
+
+func poolTestSet(phaseGroupId, winner, loser int, outcome Outcome) Set {
+	return Set{
+		Round:        1,
+		PhaseGroupId: phaseGroupId,
+		BracketType:  BracketTypeRoundRobin,
+		Outcome:      outcome,
+		CompletedAt:  1,
+		Winner:       Entrant{Id: winner},
+		Loser:        Entrant{Id: loser},
+	}
+}
+
+func TestMarkPoolRecords(t *testing.T) {
+	sets := []Set{
+		poolTestSet(1, 1, 2, OutcomeScore),
+		poolTestSet(1, 1, 3, OutcomeScore),
+		poolTestSet(1, 3, 2, OutcomeDQ),
+		poolTestSet(1, 2, 4, OutcomeTie),
+		// Another pool and a bracket set do not count.
+		poolTestSet(2, 2, 1, OutcomeScore),
+		{Round: -1, BracketType: BracketTypeDoubleElimination, CompletedAt: 1, Winner: Entrant{Id: 2}, Loser: Entrant{Id: 1}},
+	}
+	MarkPoolRecords(sets)
+	expected := [][2]string{{"2-0", "0-2-1"}, {"2-0", "1-1"}, {"1-1", "0-2-1"}, {"0-2-1", "0-0-1"}, {"1-0", "0-1"}, {"", ""}}
+	for i, set := range sets {
+		if set.Winner.Record != expected[i][0] || set.Loser.Record != expected[i][1] {
+			t.Errorf("Expected set %d records %v, got %s and %s", i, expected[i], set.Winner.Record, set.Loser.Record)
+		}
+	}
+}
+
+func TestPoolStageBracket(t *testing.T) {
+	for _, test := range []struct {
+		set                             Set
+		winners, losers, dqAndOut, pool bool
+	}{
+		{Set{Round: 2, BracketType: BracketTypeDoubleElimination}, true, false, false, false},
+		{Set{Round: -2, BracketType: BracketTypeDoubleElimination, Outcome: OutcomeDQ}, false, true, true, false},
+		{Set{Round: 2, BracketType: BracketTypeRoundRobin}, false, false, false, true},
+		{Set{Round: 3, BracketType: BracketTypeSwiss, Outcome: OutcomeDQ}, false, false, false, true},
+	} {
+		if test.set.IsWinnersBracket() != test.winners || test.set.IsLosersBracket() != test.losers || test.set.IsDQAndOut() != test.dqAndOut || test.set.IsPoolStage() != test.pool {
+			t.Errorf("Unexpected bracket of %+v", test.set)
+		}
+	}
+	if BracketTypeSwiss.StageName() != "Swiss" || BracketTypeRoundRobin.StageName() != "pools" {
+		t.Errorf("Unexpected stage names")
+	}
+}
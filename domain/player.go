@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// PlayerUpset is an upset the player scored or suffered, seen from the
+// player's side of the set.
+type PlayerUpset struct {
+	Event              EventSummary `json:"event"`
+	SetId              string       `json:"setId"`
+	Won                bool         `json:"won"`
+	Name               string       `json:"name"`
+	Characters         string       `json:"characters"`
+	Seed               int          `json:"seed"`
+	Score              string       `json:"score"`
+	Opponent           string       `json:"opponent"`
+	OpponentCharacters string       `json:"opponentCharacters"`
+	OpponentSeed       int          `json:"opponentSeed"`
+	UpsetFactor        int          `json:"upsetFactor"`
+	CompletedAt        int          `json:"completedAt"`
+}
+
+// PlayerHistory lists the upsets of a player across events, most recent
+// first.
+type PlayerHistory struct {
+	// Player is the start.gg player ID or gamer tag looked up.
+	Player string `json:"player"`
+	// Name is the player's entrant name in their most recent upset.
+	Name     string        `json:"name"`
+	Scored   []PlayerUpset `json:"scored"`
+	Suffered []PlayerUpset `json:"suffered"`
+	// GiantKillerScore adds up the upset factors of the upsets scored, so
+	// that beating much higher seeds counts for more than many close upsets.
+	GiantKillerScore int `json:"giantKillerScore"`
+}
+
+// NewPlayerHistory finds the upsets of the player, a start.gg player ID or an
+// entrant name or gamer tag matched regardless of case, in the upset threads.
+func NewPlayerHistory(player string, upsetThreads []*UpsetThread) *PlayerHistory {
+	res := &PlayerHistory{Player: strings.TrimSpace(player), Scored: []PlayerUpset{}, Suffered: []PlayerUpset{}}
+	for _, upsetThread := range upsetThreads {
+		event := EventSummary{Slug: upsetThread.Slug, Title: cmp.Or(upsetThread.Title, upsetThread.Slug)}
+		for _, item := range rankedUpsets(upsetThread) {
+			upset := PlayerUpset{Event: event, SetId: item.Id, Score: scoreOf(item), UpsetFactor: item.UpsetFactor, CompletedAt: item.CompletedAt}
+			switch {
+			case isPlayer(player, item.WinnersName, item.WinnersTag, item.WinnersPlayerIds):
+				upset.Won = true
+				upset.Name, upset.Characters, upset.Seed = item.WinnersName, item.WinnersCharacters, item.WinnersSeed
+				upset.Opponent, upset.OpponentCharacters, upset.OpponentSeed = item.LosersName, item.LosersCharacters, item.LosersSeed
+				res.Scored = append(res.Scored, upset)
+				res.GiantKillerScore += item.UpsetFactor
+			case isPlayer(player, item.LosersName, item.LosersTag, item.LosersPlayerIds):
+				upset.Name, upset.Characters, upset.Seed = item.LosersName, item.LosersCharacters, item.LosersSeed
+				upset.Opponent, upset.OpponentCharacters, upset.OpponentSeed = item.WinnersName, item.WinnersCharacters, item.WinnersSeed
+				res.Suffered = append(res.Suffered, upset)
+			}
+		}
+	}
+	mostRecentFirst := func(i, j PlayerUpset) int {
+		return cmp.Or(
+			cmp.Compare(j.CompletedAt, i.CompletedAt),
+			strings.Compare(i.SetId, j.SetId),
+		)
+	}
+	slices.SortFunc(res.Scored, mostRecentFirst)
+	slices.SortFunc(res.Suffered, mostRecentFirst)
+	latest := 0
+	for _, upset := range slices.Concat(res.Scored, res.Suffered) {
+		if upset.CompletedAt > latest {
+			latest, res.Name = upset.CompletedAt, upset.Name
+		}
+	}
+	return res
+}
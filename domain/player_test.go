@@ -0,0 +1,36 @@
+package domain
+
+import "testing"
+
+func TestNewPlayerHistory(t *testing.T) {
+	score := "3-1"
+	upsetThreads := []*UpsetThread{
+		{
+			Slug:  "tournament/a/event/singles",
+			Title: "A",
+			Winners: []UpsetThreadItem{
+				{Id: "1", WinnersName: "Zomba", WinnersPlayerIds: []int{1}, WinnersCharacters: "R.O.B.", WinnersSeed: 17, Score: &score, LosersName: "Sparg0", LosersPlayerIds: []int{2}, LosersCharacters: "Cloud", LosersSeed: 1, UpsetFactor: 6, CompletedAt: 10},
+				{Id: "2", WinnersName: "Zomba", WinnersPlayerIds: []int{1}, WinnersSeed: 17, Score: &score, LosersName: "Tweek", LosersSeed: 9, UpsetFactor: 2, CompletedAt: 20, Outcome: OutcomeDQ},
+			},
+		},
+		{
+			Slug: "tournament/b/event/singles",
+			Losers: []UpsetThreadItem{
+				{Id: "1", WinnersName: "Mkleo", WinnersSeed: 40, Score: &score, LosersName: "LG | Zomba", LosersTag: "Zomba", LosersPlayerIds: []int{1}, LosersSeed: 4, UpsetFactor: 8, CompletedAt: 30},
+				{Id: "2", WinnersName: "LG | Zomba", WinnersTag: "Zomba", WinnersPlayerIds: []int{1}, WinnersSeed: 4, Score: &score, LosersName: "Tweek", LosersSeed: 1, UpsetFactor: 2, CompletedAt: 40},
+			},
+		},
+	}
+	for _, player := range []string{"1", "zomba"} {
+		res := NewPlayerHistory(player, upsetThreads)
+		if res.Name != "LG | Zomba" || res.GiantKillerScore != 8 {
+			t.Errorf("Expected LG | Zomba with a giant killer score of 8 for %s, got %s and %d", player, res.Name, res.GiantKillerScore)
+		}
+		if len(res.Scored) != 2 || res.Scored[0].CompletedAt != 40 || res.Scored[1].Opponent != "Sparg0" || res.Scored[1].Characters != "R.O.B." || !res.Scored[1].Won {
+			t.Errorf("Expected the upsets over Tweek and Sparg0 for %s, got %v", player, res.Scored)
+		}
+		if len(res.Suffered) != 1 || res.Suffered[0].Opponent != "Mkleo" || res.Suffered[0].Seed != 4 || res.Suffered[0].Event.Title != "tournament/b/event/singles" {
+			t.Errorf("Expected the upset suffered to Mkleo for %s, got %v", player, res.Suffered)
+		}
+	}
+}
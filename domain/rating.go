@@ -0,0 +1,205 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	// InitialRating, InitialDeviation and InitialVolatility are the Glicko-2
+	// defaults for players without a rated set.
+	InitialRating     = 1500
+	InitialDeviation  = 350
+	InitialVolatility = 0.06
+	// glickoScale converts ratings to and from the Glicko-2 scale.
+	glickoScale = 173.7178
+	// glickoTau constrains how quickly volatility changes.
+	glickoTau = 0.5
+	// glickoEpsilon is the convergence tolerance of the volatility.
+	glickoEpsilon = 0.000001
+)
+
+// Rating is a Glicko-2 rating on the Glicko scale, where new players start at
+// 1500 with a deviation of 350.
+type Rating struct {
+	Value      float64 `json:"rating"`
+	Deviation  float64 `json:"deviation"`
+	Volatility float64 `json:"volatility"`
+}
+
+func NewRating() Rating {
+	return Rating{Value: InitialRating, Deviation: InitialDeviation, Volatility: InitialVolatility}
+}
+
+// RatingResult is the outcome of a game against an opponent, with Score 1 for
+// a win and 0 for a loss.
+type RatingResult struct {
+	Opponent Rating
+	Score    float64
+}
+
+func (r Rating) mu() float64 {
+	return (r.Value - InitialRating) / glickoScale
+}
+
+func (r Rating) phi() float64 {
+	return r.Deviation / glickoScale
+}
+
+func glickoG(phi float64) float64 {
+	return 1 / math.Sqrt(1+3*phi*phi/(math.Pi*math.Pi))
+}
+
+func glickoE(mu, opponentMu, opponentPhi float64) float64 {
+	return 1 / (1 + math.Exp(-glickoG(opponentPhi)*(mu-opponentMu)))
+}
+
+// WinProbability is the chance that a player rated r beats the opponent,
+// accounting for the uncertainty of both ratings.
+func WinProbability(r, opponent Rating) float64 {
+	phi := math.Sqrt(r.phi()*r.phi() + opponent.phi()*opponent.phi())
+	return glickoE(r.mu(), opponent.mu(), phi)
+}
+
+// volatility finds the new volatility with the Illinois algorithm, as in
+// step 5 of Glickman's description of Glicko-2.
+func (r Rating) volatility(delta, v float64) float64 {
+	phi := r.phi()
+	a := math.Log(r.Volatility * r.Volatility)
+	f := func(x float64) float64 {
+		ex := math.Exp(x)
+		d := phi*phi + v + ex
+		return ex*(delta*delta-phi*phi-v-ex)/(2*d*d) - (x-a)/(glickoTau*glickoTau)
+	}
+	A := a
+	var B float64
+	if delta*delta > phi*phi+v {
+		B = math.Log(delta*delta - phi*phi - v)
+	} else {
+		k := 1.0
+		for f(a-k*glickoTau) < 0 {
+			k++
+		}
+		B = a - k*glickoTau
+	}
+	fA, fB := f(A), f(B)
+	for math.Abs(B-A) > glickoEpsilon {
+		C := A + (A-B)*fA/(fB-fA)
+		fC := f(C)
+		if fC*fB <= 0 {
+			A, fA = B, fB
+		} else {
+			fA /= 2
+		}
+		B, fB = C, fC
+	}
+	return math.Exp(A / 2)
+}
+
+// Update returns the rating after a rating period with the results. Without
+// results only the deviation grows.
+func (r Rating) Update(results []RatingResult) Rating {
+	mu, phi := r.mu(), r.phi()
+	if len(results) == 0 {
+		return Rating{
+			Value:      r.Value,
+			Deviation:  math.Sqrt(phi*phi+r.Volatility*r.Volatility) * glickoScale,
+			Volatility: r.Volatility,
+		}
+	}
+	var vInverse, improvement float64
+	for _, result := range results {
+		g := glickoG(result.Opponent.phi())
+		e := glickoE(mu, result.Opponent.mu(), result.Opponent.phi())
+		vInverse += g * g * e * (1 - e)
+		improvement += g * (result.Score - e)
+	}
+	v := 1 / vInverse
+	volatility := r.volatility(v*improvement, v)
+	phiStar := math.Sqrt(phi*phi + volatility*volatility)
+	newPhi := 1 / math.Sqrt(1/(phiStar*phiStar)+1/v)
+	return Rating{
+		Value:      (mu+newPhi*newPhi*improvement)*glickoScale + InitialRating,
+		Deviation:  newPhi * glickoScale,
+		Volatility: volatility,
+	}
+}
+
+// RatingChange records the rating of a player after a set.
+type RatingChange struct {
+	Event          string  `json:"event"`
+	SetId          string  `json:"setId"`
+	Opponent       string  `json:"opponent"`
+	Won            bool    `json:"won"`
+	Tie            bool    `json:"tie"`
+	WinProbability float64 `json:"winProbability"`
+	Rating         Rating  `json:"rating"`
+	CompletedAt    int     `json:"completedAt"`
+}
+
+// PlayerRating is the current rating of a player and its history, oldest
+// first. Player is the key of the player, see UpsetThreadItem.WinnersKey.
+type PlayerRating struct {
+	Player  string         `json:"player"`
+	Name    string         `json:"name"`
+	Rating  Rating         `json:"rating"`
+	History []RatingChange `json:"history"`
+}
+
+func NewPlayerRating(player string) *PlayerRating {
+	return &PlayerRating{Player: player, Rating: NewRating(), History: []RatingChange{}}
+}
+
+// IsRateable reports whether the set updates the ratings of its players. DQs
+// and forfeits say nothing about skill.
+func IsRateable(item UpsetThreadItem) bool {
+	return item.CompletedAt > 0 && item.Score != nil && item.Outcome != OutcomeDQ && item.Outcome != OutcomeForfeit
+}
+
+// RateSet updates the ratings of the set's winner and loser, each set being a
+// rating period of its own, and returns the winner's pre-match win
+// probability. A tie counts as half a win for both players and returns 0, as
+// neither player won.
+func RateSet(winner, loser *PlayerRating, event string, item UpsetThreadItem) float64 {
+	res := WinProbability(winner.Rating, loser.Rating)
+	tie := item.Outcome == OutcomeTie
+	score := 1.0
+	if tie {
+		score = 0.5
+	}
+	winnerRating := winner.Rating.Update([]RatingResult{{Opponent: loser.Rating, Score: score}})
+	loserRating := loser.Rating.Update([]RatingResult{{Opponent: winner.Rating, Score: 1 - score}})
+	winner.Name, winner.Rating = item.WinnersName, winnerRating
+	loser.Name, loser.Rating = item.LosersName, loserRating
+	winner.History = append(winner.History, RatingChange{
+		Event: event, SetId: item.Id, Opponent: item.LosersName, Won: !tie, Tie: tie,
+		WinProbability: res, Rating: winnerRating, CompletedAt: item.CompletedAt,
+	})
+	loser.History = append(loser.History, RatingChange{
+		Event: event, SetId: item.Id, Opponent: item.WinnersName, Won: false, Tie: tie,
+		WinProbability: 1 - res, Rating: loserRating, CompletedAt: item.CompletedAt,
+	})
+	if tie {
+		return 0
+	}
+	return res
+}
+
+// WinChance formats the winner's pre-match win probability as a percentage,
+// or is empty for sets that were not rated.
+func (i UpsetThreadItem) WinChance() string {
+	if i.WinProbability <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%.0f%%", i.WinProbability*100)
+}
+
+// WinnersKey identifies the winner across events for ratings.
+func (i UpsetThreadItem) WinnersKey() string {
+	return playerKey(i.WinnersName, i.WinnersTag, i.WinnersPlayerIds)
+}
+
+// LosersKey identifies the loser across events for ratings.
+func (i UpsetThreadItem) LosersKey() string {
+	return playerKey(i.LosersName, i.LosersTag, i.LosersPlayerIds)
+}
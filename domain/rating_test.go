@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"math"
+	"testing"
+)
+
+// TestRatingUpdate checks the example of Glickman's description of Glicko-2.
+func TestRatingUpdate(t *testing.T) {
+	res := Rating{Value: 1500, Deviation: 200, Volatility: 0.06}.Update([]RatingResult{
+		{Opponent: Rating{Value: 1400, Deviation: 30}, Score: 1},
+		{Opponent: Rating{Value: 1550, Deviation: 100}, Score: 0},
+		{Opponent: Rating{Value: 1700, Deviation: 300}, Score: 0},
+	})
+	if math.Abs(res.Value-1464.06) > 0.01 || math.Abs(res.Deviation-151.52) > 0.01 || math.Abs(res.Volatility-0.05999) > 0.00001 {
+		t.Errorf("Expected 1464.06, 151.52 and 0.05999, got %+v", res)
+	}
+	if res := NewRating().Update(nil); res.Value != InitialRating || res.Deviation <= InitialDeviation {
+		t.Errorf("Expected the deviation to grow without results, got %+v", res)
+	}
+}
+
+func TestWinProbability(t *testing.T) {
+	if res := WinProbability(NewRating(), NewRating()); res != 0.5 {
+		t.Errorf("Expected even odds between new players, got %v", res)
+	}
+	strong, weak := Rating{Value: 1900, Deviation: 50}, Rating{Value: 1500, Deviation: 50}
+	if res := WinProbability(strong, weak); res < 0.85 || math.Abs(res+WinProbability(weak, strong)-1) > 1e-9 {
+		t.Errorf("Expected the stronger player to be the clear favourite, got %v", res)
+	}
+}
+
+func TestRateSet(t *testing.T) {
+	score := "3-0"
+	winner, loser := NewPlayerRating("1"), NewPlayerRating("sonix")
+	item := UpsetThreadItem{Id: "10", WinnersName: "Sparg0", LosersName: "Sonix", Score: &score, CompletedAt: 5}
+	if res := RateSet(winner, loser, "tournament/a/event/singles", item); res != 0.5 {
+		t.Errorf("Expected even odds between new players, got %v", res)
+	}
+	if winner.Rating.Value <= InitialRating || loser.Rating.Value >= InitialRating || winner.Name != "Sparg0" {
+		t.Errorf("Expected the winner to gain and the loser to lose rating, got %+v and %+v", winner, loser)
+	}
+	if len(winner.History) != 1 || winner.History[0] != (RatingChange{Event: "tournament/a/event/singles", SetId: "10", Opponent: "Sonix", Won: true, WinProbability: 0.5, Rating: winner.Rating, CompletedAt: 5}) {
+		t.Errorf("Expected the set in the winner's history, got %+v", winner.History)
+	}
+	item.WinProbability = RateSet(winner, loser, "tournament/a/event/singles", item)
+	if item.WinProbability <= 0.5 || item.WinChance() == "" {
+		t.Errorf("Expected the winner to be favoured in the rematch, got %v", item.WinProbability)
+	}
+
+	tie := "2-2"
+	first, second := NewPlayerRating("1"), NewPlayerRating("2")
+	item = UpsetThreadItem{Id: "11", WinnersName: "Sparg0", LosersName: "Sonix", Score: &tie, Outcome: OutcomeTie, CompletedAt: 6}
+	if res := RateSet(first, second, "tournament/a/event/singles", item); res != 0 {
+		t.Errorf("Expected no win probability for a tie, got %v", res)
+	}
+	if first.Rating.Value != InitialRating || second.Rating.Value != InitialRating {
+		t.Errorf("Expected a tie between even players to keep their ratings, got %+v and %+v", first.Rating, second.Rating)
+	}
+	if change := first.History[0]; change.Won || !change.Tie || change.WinProbability != 0.5 {
+		t.Errorf("Expected a tie in the history, got %+v", change)
+	}
+	if change := second.History[0]; change.Won || !change.Tie {
+		t.Errorf("Expected a tie in the history, got %+v", change)
+	}
+}
@@ -0,0 +1,181 @@
+package domain
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Outcome is how a set was decided.
+type Outcome string
+
+const (
+	// OutcomeScore is a set reported with the games each entrant won.
+	OutcomeScore Outcome = "score"
+	// OutcomeWin is a set reported with its winner only.
+	OutcomeWin Outcome = "W-L"
+	OutcomeDQ  Outcome = "DQ"
+	// OutcomeForfeit is a set the loser was disqualified from after games
+	// were played, such as when they left midway.
+	OutcomeForfeit Outcome = "forfeit"
+	// OutcomeTie is a set without a winner, as in round robin pools.
+	OutcomeTie Outcome = "tie"
+)
+
+// Scores shown for the outcomes that do not have games to count.
+const (
+	ScoreWin     = "W-L"
+	ScoreDQ      = "DQ"
+	ScoreForfeit = "FF"
+)
+
+// dqScore is the score start.gg reports for a disqualified entrant.
+const dqScore = -1
+
+// displayScorePattern matches "<name> <score> - <name> <score>", such as
+// "Player 3 1 - FaZe | Sparg0 3", where a score is a number, W, L or DQ.
+var displayScorePattern = regexp.MustCompile(`^(.*) (-?\d+|W|L|DQ) - (.*) (-?\d+|W|L|DQ)$`)
+
+// ScoreOutcome returns the outcome of a set from the score it was given.
+func ScoreOutcome(score string) Outcome {
+	switch score {
+	case "":
+		return ""
+	case ScoreWin:
+		return OutcomeWin
+	case ScoreDQ:
+		return OutcomeDQ
+	case ScoreForfeit:
+		return OutcomeForfeit
+	}
+	if first, second, ok := strings.Cut(score, "-"); ok && first == second {
+		return OutcomeTie
+	}
+	return OutcomeScore
+}
+
+func formatScore(winnerScore, loserScore int) string {
+	return strconv.Itoa(winnerScore) + "-" + strconv.Itoa(loserScore)
+}
+
+// gameScores counts the games won by each entrant. Games without a winner
+// have not been played.
+func gameScores(games *[]Game, winner Entrant) (int, int, bool) {
+	if games == nil {
+		return 0, 0, false
+	}
+	winnerScore, loserScore := 0, 0
+	for _, game := range *games {
+		if game.WinnerId == 0 {
+			continue
+		}
+		if game.WinnerId == winner.Id {
+			winnerScore++
+		} else {
+			loserScore++
+		}
+	}
+	return winnerScore, loserScore, winnerScore+loserScore > 0
+}
+
+// splitDisplayScore returns the two scores of the display score. The names
+// of the entrants are cut off first, so that names containing numbers or
+// dashes are not mistaken for scores.
+func splitDisplayScore(displayScore string, names ...string) (string, string, bool) {
+	for _, first := range names {
+		for _, second := range names {
+			if first == second {
+				continue
+			}
+			rest, ok := strings.CutPrefix(displayScore, first+" ")
+			if !ok {
+				continue
+			}
+			firstScore, rest, ok := strings.Cut(rest, " - ")
+			if !ok {
+				continue
+			}
+			if secondScore, ok := strings.CutPrefix(rest, second+" "); ok {
+				return firstScore, secondScore, true
+			}
+		}
+	}
+	match := displayScorePattern.FindStringSubmatch(displayScore)
+	if match == nil {
+		return "", "", false
+	}
+	return match[2], match[4], true
+}
+
+// parseDisplayScore reads the scores out of start.gg's display score. The
+// winner is known and has the higher score, so the scores are returned in
+// that order.
+func parseDisplayScore(displayScore string, winner, loser Entrant) (int, int, Outcome) {
+	displayScore = strings.TrimSpace(displayScore)
+	if displayScore == ScoreDQ {
+		return 0, 0, OutcomeDQ
+	}
+	first, second, ok := splitDisplayScore(displayScore, winner.Name, loser.Name)
+	if !ok {
+		return 0, 0, ""
+	}
+	if first == ScoreDQ || second == ScoreDQ {
+		return 0, 0, OutcomeDQ
+	}
+	firstScore, err := strconv.Atoi(first)
+	if err != nil {
+		return 0, 0, OutcomeWin
+	}
+	secondScore, err := strconv.Atoi(second)
+	if err != nil {
+		return 0, 0, OutcomeWin
+	}
+	if firstScore == dqScore || secondScore == dqScore {
+		return 0, 0, OutcomeDQ
+	}
+	return max(firstScore, secondScore), min(firstScore, secondScore), OutcomeScore
+}
+
+// initScore works out how the set was decided and its score from the winner.
+// The scores start.gg reports for each slot are preferred, then the games
+// played and the display score.
+func initScore(games *[]Game, displayScore string, winner, loser Entrant, hasWinner bool) (*string, Outcome) {
+	gamesWinnerScore, gamesLoserScore, hasGames := gameScores(games, winner)
+	var outcome Outcome
+	var winnerScore, loserScore int
+	switch {
+	case winner.ScoreLabel == "W" || loser.ScoreLabel == "L":
+		outcome = OutcomeWin
+	case loser.Score != nil && *loser.Score == dqScore && hasGames:
+		outcome = OutcomeForfeit
+	case displayScore == ScoreDQ || (loser.Score != nil && *loser.Score == dqScore):
+		outcome = OutcomeDQ
+	case winner.Score != nil && loser.Score != nil:
+		winnerScore, loserScore, outcome = *winner.Score, *loser.Score, OutcomeScore
+	default:
+		winnerScore, loserScore, outcome = parseDisplayScore(displayScore, winner, loser)
+		// The games can be missing from the display score or incomplete, so
+		// whichever has more games won is used.
+		if hasGames && (outcome == "" || (outcome == OutcomeScore && gamesWinnerScore >= winnerScore)) {
+			winnerScore, loserScore, outcome = gamesWinnerScore, gamesLoserScore, OutcomeScore
+		}
+	}
+	if outcome == OutcomeScore && !hasWinner && winnerScore == loserScore {
+		outcome = OutcomeTie
+	}
+	if outcome == OutcomeScore && winnerScore == 0 && loserScore == 0 {
+		outcome = OutcomeWin
+	}
+	var score string
+	switch outcome {
+	case OutcomeWin:
+		score = ScoreWin
+	case OutcomeDQ:
+		score = ScoreDQ
+	case OutcomeForfeit:
+		score = ScoreForfeit
+	case OutcomeScore, OutcomeTie:
+		score = formatScore(winnerScore, loserScore)
+	}
+	return &score, outcome
+}
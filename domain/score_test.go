@@ -0,0 +1,53 @@
+package domain
+
+import "testing"
+
+func TestSetOutcome(t *testing.T) {
+	five, four, zero, dq := 5, 4, 0, -1
+	winner := Entrant{Id: 1, Name: "FaZe | Sparg0", InitialSeed: 1}
+	loser := Entrant{Id: 2, Name: "Player 3 - 1", InitialSeed: 9}
+	withScore := func(entrant Entrant, score *int, label string) Entrant {
+		entrant.Score, entrant.ScoreLabel = score, label
+		return entrant
+	}
+	games := &[]Game{{Id: 1, WinnerId: 2}, {Id: 2, WinnerId: 1}, {Id: 3, WinnerId: 1}, {Id: 4, WinnerId: 0}}
+	tests := []struct {
+		name         string
+		displayScore string
+		winnerId     int
+		entrants     []Entrant
+		games        *[]Game
+		score        string
+		outcome      Outcome
+		notable      bool
+	}{
+		{"first to five from slots", "", 1, []Entrant{withScore(winner, &five, ""), withScore(loser, &four, "")}, nil, "5-4", OutcomeScore, true},
+		{"first to five from display score", "FaZe | Sparg0 5 - Player 3 - 1 4", 1, []Entrant{winner, loser}, nil, "5-4", OutcomeScore, true},
+		{"first to ten from display score", "Player 3 - 1 2 - FaZe | Sparg0 10", 1, []Entrant{winner, loser}, nil, "10-2", OutcomeScore, false},
+		{"winner only from slots", "", 1, []Entrant{withScore(winner, nil, "W"), withScore(loser, nil, "L")}, nil, ScoreWin, OutcomeWin, false},
+		{"winner only from display score", "FaZe | Sparg0 W - Player 3 - 1 L", 1, []Entrant{winner, loser}, nil, ScoreWin, OutcomeWin, false},
+		{"winner without games", "", 1, []Entrant{withScore(winner, &zero, ""), withScore(loser, &zero, "")}, nil, ScoreWin, OutcomeWin, false},
+		{"DQ from slots", "", 1, []Entrant{withScore(winner, &zero, ""), withScore(loser, &dq, "")}, nil, ScoreDQ, OutcomeDQ, false},
+		{"forfeit after games", "", 1, []Entrant{withScore(winner, &zero, ""), withScore(loser, &dq, "")}, games, ScoreForfeit, OutcomeForfeit, false},
+		{"tie", "", 0, []Entrant{withScore(winner, &four, ""), withScore(loser, &four, "")}, nil, "4-4", OutcomeTie, false},
+		{"games only", "", 1, []Entrant{winner, loser}, games, "2-1", OutcomeScore, true},
+		{"nothing reported", "", 1, []Entrant{winner, loser}, nil, "", "", false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			set := NewSet("1", test.displayScore, nil, 9, 1, 0, test.winnerId, test.entrants, test.games, 0)
+			if *set.Score != test.score || set.Outcome != test.outcome {
+				t.Errorf("Expected %s (%s), got %s (%s)", test.score, test.outcome, *set.Score, set.Outcome)
+			}
+			if set.IsNotable(DefaultNotableRules) != test.notable {
+				t.Errorf("Expected notable %t, got %t", test.notable, set.IsNotable(DefaultNotableRules))
+			}
+			if test.outcome == OutcomeTie && set.UpsetFactor != 0 {
+				t.Errorf("Expected no upset factor for a tie, got %d", set.UpsetFactor)
+			}
+			if ScoreOutcome(*set.Score) != test.outcome {
+				t.Errorf("Expected outcome %s from score, got %s", test.outcome, ScoreOutcome(*set.Score))
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package domain
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Season groups the events of a season or circuit so that their upsets can
+// be ranked together. Events are start.gg event slugs, whether or not they
+// are still tracked.
+type Season struct {
+	Id     string   `json:"id"`
+	Name   string   `json:"name"`
+	Events []string `json:"events"`
+}
+
+const (
+	// LeaderboardSize is the number of entries of each season leaderboard.
+	LeaderboardSize = 10
+	// TopSeed is the worst seed counted as a top seed in
+	// SeasonLeaderboard.UpsetTopSeeds.
+	TopSeed = 8
+)
+
+// EventSummary names an event whose upsets are listed with those of other
+// events.
+type EventSummary struct {
+	Slug  string `json:"slug"`
+	Title string `json:"title"`
+}
+
+// SeasonUpset is an upset of one of the season's events.
+type SeasonUpset struct {
+	Event       EventSummary `json:"event"`
+	SetId       string       `json:"setId"`
+	WinnersName string       `json:"winnersName"`
+	WinnersSeed int          `json:"winnersSeed"`
+	Score       string       `json:"score"`
+	LosersName  string       `json:"losersName"`
+	LosersSeed  int          `json:"losersSeed"`
+	UpsetFactor int          `json:"upsetFactor"`
+	CompletedAt int          `json:"completedAt"`
+}
+
+// PlayerUpsets counts the upsets a player won, or was on the losing side of,
+// across the season.
+type PlayerUpsets struct {
+	Name             string `json:"name"`
+	Upsets           int    `json:"upsets"`
+	TotalUpsetFactor int    `json:"totalUpsetFactor"`
+	// completedAt is when the player's name was last seen, so that players
+	// who changed sponsor are shown with their current name.
+	completedAt int
+}
+
+// SeasonLeaderboard ranks the upsets of every event of a season.
+type SeasonLeaderboard struct {
+	Id     string         `json:"id"`
+	Name   string         `json:"name"`
+	Events []EventSummary `json:"events"`
+	// BiggestUpsets has the upsets with the highest upset factor.
+	BiggestUpsets []SeasonUpset `json:"biggestUpsets"`
+	// MostUpsets has the players who won the most upsets.
+	MostUpsets []PlayerUpsets `json:"mostUpsets"`
+	// UpsetTopSeeds has the top seeds who were upset the most.
+	UpsetTopSeeds []PlayerUpsets `json:"upsetTopSeeds"`
+}
+
+// playerKey identifies a player across events by their start.gg player ID,
+// falling back to their gamer tag for teams and sets stored without IDs.
+func playerKey(name, tag string, playerIds []int) string {
+	if len(playerIds) == 1 {
+		return strconv.Itoa(playerIds[0])
+	}
+	return strings.ToLower(cmp.Or(tag, name))
+}
+
+// isRankedUpset reports whether the set counts towards leaderboards and
+// player histories. DQs and forfeits are not upsets, however far apart the
+// seeds are.
+func isRankedUpset(item UpsetThreadItem) bool {
+	return item.UpsetFactor > 0 && item.CompletedAt > 0 && item.Outcome != OutcomeDQ && item.Outcome != OutcomeForfeit
+}
+
+// rankedUpsets returns the ranked upsets of the thread, once each whichever
+// sections they are in.
+func rankedUpsets(upsetThread *UpsetThread) []UpsetThreadItem {
+	var res []UpsetThreadItem
+	seen := make(map[string]bool)
+	for _, items := range [][]UpsetThreadItem{upsetThread.GrandFinals, upsetThread.Winners, upsetThread.Losers, upsetThread.Pools, upsetThread.Notables, upsetThread.DQs, upsetThread.Other} {
+		for _, item := range items {
+			if seen[item.Id] || !isRankedUpset(item) {
+				continue
+			}
+			seen[item.Id] = true
+			res = append(res, item)
+		}
+	}
+	return res
+}
+
+func scoreOf(item UpsetThreadItem) string {
+	if item.Score == nil {
+		return ""
+	}
+	return *item.Score
+}
+
+func countUpset(players map[string]*PlayerUpsets, key, name string, item UpsetThreadItem) {
+	player, ok := players[key]
+	if !ok {
+		player = &PlayerUpsets{}
+		players[key] = player
+	}
+	player.Upsets++
+	player.TotalUpsetFactor += item.UpsetFactor
+	if item.CompletedAt >= player.completedAt {
+		player.Name, player.completedAt = name, item.CompletedAt
+	}
+}
+
+func topPlayers(players map[string]*PlayerUpsets, size int) []PlayerUpsets {
+	res := make([]PlayerUpsets, 0, len(players))
+	for _, player := range players {
+		res = append(res, *player)
+	}
+	slices.SortFunc(res, func(i, j PlayerUpsets) int {
+		return cmp.Or(
+			cmp.Compare(j.Upsets, i.Upsets),
+			cmp.Compare(j.TotalUpsetFactor, i.TotalUpsetFactor),
+			strings.Compare(strings.ToLower(i.Name), strings.ToLower(j.Name)),
+		)
+	})
+	return res[:min(size, len(res))]
+}
+
+// NewSeasonLeaderboard ranks the upsets of the season's upset threads, keeping
+// size entries in each leaderboard. Sets are counted once, whichever sections
+// of their thread they are in.
+func NewSeasonLeaderboard(season Season, upsetThreads []*UpsetThread, size int) *SeasonLeaderboard {
+	res := &SeasonLeaderboard{Id: season.Id, Name: season.Name, Events: []EventSummary{}}
+	upsets := []SeasonUpset{}
+	winners := make(map[string]*PlayerUpsets)
+	losers := make(map[string]*PlayerUpsets)
+	for _, upsetThread := range upsetThreads {
+		event := EventSummary{Slug: upsetThread.Slug, Title: cmp.Or(upsetThread.Title, upsetThread.Slug)}
+		res.Events = append(res.Events, event)
+		for _, item := range rankedUpsets(upsetThread) {
+			upsets = append(upsets, SeasonUpset{
+				Event:       event,
+				SetId:       item.Id,
+				WinnersName: item.WinnersName,
+				WinnersSeed: item.WinnersSeed,
+				Score:       scoreOf(item),
+				LosersName:  item.LosersName,
+				LosersSeed:  item.LosersSeed,
+				UpsetFactor: item.UpsetFactor,
+				CompletedAt: item.CompletedAt,
+			})
+			countUpset(winners, playerKey(item.WinnersName, item.WinnersTag, item.WinnersPlayerIds), item.WinnersName, item)
+			if item.LosersSeed > 0 && item.LosersSeed <= TopSeed {
+				countUpset(losers, playerKey(item.LosersName, item.LosersTag, item.LosersPlayerIds), item.LosersName, item)
+			}
+		}
+	}
+	slices.SortFunc(upsets, func(i, j SeasonUpset) int {
+		return cmp.Or(
+			cmp.Compare(j.UpsetFactor, i.UpsetFactor),
+			cmp.Compare(i.CompletedAt, j.CompletedAt),
+			strings.Compare(i.Event.Slug, j.Event.Slug),
+			strings.Compare(i.SetId, j.SetId),
+		)
+	})
+	res.BiggestUpsets = upsets[:min(size, len(upsets))]
+	res.MostUpsets = topPlayers(winners, size)
+	res.UpsetTopSeeds = topPlayers(losers, size)
+	return res
+}
@@ -0,0 +1,43 @@
+package domain
+
+import "testing"
+
+func TestNewSeasonLeaderboard(t *testing.T) {
+	score := "3-1"
+	season := Season{Id: "circuit", Name: "Circuit", Events: []string{"tournament/a/event/singles", "tournament/b/event/singles"}}
+	upsetThreads := []*UpsetThread{
+		{
+			Slug:  "tournament/a/event/singles",
+			Title: "A",
+			Winners: []UpsetThreadItem{
+				{Id: "1", WinnersName: "Zomba", WinnersPlayerIds: []int{1}, WinnersSeed: 17, Score: &score, LosersName: "Sparg0", LosersPlayerIds: []int{2}, LosersSeed: 1, UpsetFactor: 6, CompletedAt: 10},
+				{Id: "2", WinnersName: "Tweek", WinnersPlayerIds: []int{3}, WinnersSeed: 9, Score: &score, LosersName: "Sparg0", LosersPlayerIds: []int{2}, LosersSeed: 1, UpsetFactor: 4, CompletedAt: 20, Outcome: OutcomeDQ},
+			},
+			Other: []UpsetThreadItem{
+				{Id: "3", WinnersName: "Tweek", WinnersPlayerIds: []int{3}, WinnersSeed: 9, Score: &score, LosersName: "Mkleo", LosersPlayerIds: []int{4}, LosersSeed: 12, UpsetFactor: 1, CompletedAt: 30},
+			},
+			Watched: []UpsetThreadItem{
+				{Id: "1", WinnersName: "Zomba", WinnersPlayerIds: []int{1}, WinnersSeed: 17, Score: &score, LosersName: "Sparg0", LosersPlayerIds: []int{2}, LosersSeed: 1, UpsetFactor: 6, CompletedAt: 10},
+			},
+		},
+		{
+			Slug: "tournament/b/event/singles",
+			Losers: []UpsetThreadItem{
+				{Id: "1", WinnersName: "LG | Zomba", WinnersPlayerIds: []int{1}, WinnersSeed: 33, Score: &score, LosersName: "FaZe | Sparg0", LosersPlayerIds: []int{2}, LosersSeed: 2, UpsetFactor: 7, CompletedAt: 40},
+			},
+		},
+	}
+	res := NewSeasonLeaderboard(season, upsetThreads, 2)
+	if len(res.Events) != 2 || res.Events[1].Title != "tournament/b/event/singles" {
+		t.Errorf("Expected both events, named by slug without a title, got %v", res.Events)
+	}
+	if len(res.BiggestUpsets) != 2 || res.BiggestUpsets[0].UpsetFactor != 7 || res.BiggestUpsets[1].Event.Title != "A" {
+		t.Errorf("Expected the upsets with factors 7 and 6, got %v", res.BiggestUpsets)
+	}
+	if len(res.MostUpsets) != 2 || res.MostUpsets[0].Name != "LG | Zomba" || res.MostUpsets[0].Upsets != 2 || res.MostUpsets[0].TotalUpsetFactor != 13 || res.MostUpsets[1].Name != "Tweek" {
+		t.Errorf("Expected Zomba with 2 upsets under their latest name, then Tweek, got %v", res.MostUpsets)
+	}
+	if len(res.UpsetTopSeeds) != 1 || res.UpsetTopSeeds[0].Name != "FaZe | Sparg0" || res.UpsetTopSeeds[0].Upsets != 2 {
+		t.Errorf("Expected Sparg0 upset twice as a top seed, got %v", res.UpsetTopSeeds)
+	}
+}
@@ -0,0 +1,199 @@
+package domain
+
+import (
+	"cmp"
+	"math"
+	"slices"
+)
+
+// UpsetFactorBuckets label the ranges of upset factor counted for each phase
+// in seeding reports.
+var UpsetFactorBuckets = []string{"1-2", "3-4", "5-6", "7+"}
+
+func upsetFactorBucket(upsetFactor int) int {
+	return min((upsetFactor-1)/2, len(UpsetFactorBuckets)-1)
+}
+
+// SeedingMetrics measure how closely the entrants of an event placed to
+// their seeds.
+type SeedingMetrics struct {
+	Entrants int `json:"entrants"`
+	// MeanTierError is the average number of placement tiers, such as 5th
+	// or 9th, between the placement an entrant's seed projects and their
+	// actual placement.
+	MeanTierError float64 `json:"meanTierError"`
+	// Spearman is the rank correlation of seeds and placements, 1 when the
+	// event went exactly to seed.
+	Spearman float64 `json:"spearman"`
+}
+
+// PhaseUpsets counts the upsets of a phase by upset factor, in the order of
+// UpsetFactorBuckets.
+type PhaseUpsets struct {
+	Phase  string `json:"phase"`
+	Counts []int  `json:"counts"`
+}
+
+// SeedingBenchmark compares the seeding of an event with other events.
+type SeedingBenchmark struct {
+	Events        int     `json:"events"`
+	MeanTierError float64 `json:"meanTierError"`
+	Spearman      float64 `json:"spearman"`
+	// BetterThan is the share of the events whose seeds and placements were
+	// less correlated.
+	BetterThan float64 `json:"betterThan"`
+}
+
+// SeedingReport gives tournament organisers feedback on their seeding once
+// the event is over.
+type SeedingReport struct {
+	Title     string           `json:"title"`
+	Slug      string           `json:"slug"`
+	Metrics   SeedingMetrics   `json:"metrics"`
+	Buckets   []string         `json:"buckets"`
+	Phases    []PhaseUpsets    `json:"phases"`
+	Benchmark SeedingBenchmark `json:"benchmark"`
+}
+
+type seededEntrant struct {
+	seed, placement, completedAt int
+}
+
+// seededEntrants returns the seed and placement of every entrant with a set
+// in the thread, as of their most recent set. Seeds are unique within an
+// event, so they identify the entrants.
+func seededEntrants(upsetThread *UpsetThread) []seededEntrant {
+	entrants := make(map[int]seededEntrant)
+	add := func(seed, placement, completedAt int) {
+		if seed <= 0 || placement <= 0 {
+			return
+		}
+		if entrant, ok := entrants[seed]; !ok || completedAt >= entrant.completedAt {
+			entrants[seed] = seededEntrant{seed, placement, completedAt}
+		}
+	}
+	for _, items := range [][]UpsetThreadItem{upsetThread.GrandFinals, upsetThread.Winners, upsetThread.Losers, upsetThread.Pools, upsetThread.Notables, upsetThread.DQs, upsetThread.Other} {
+		for _, item := range items {
+			add(item.WinnersSeed, item.WinnersPlacement, item.CompletedAt)
+			add(item.LosersSeed, item.LosersPlacement, item.CompletedAt)
+		}
+	}
+	res := make([]seededEntrant, 0, len(entrants))
+	for _, entrant := range entrants {
+		res = append(res, entrant)
+	}
+	slices.SortFunc(res, func(i, j seededEntrant) int {
+		return cmp.Compare(i.seed, j.seed)
+	})
+	return res
+}
+
+// ranks returns the rank of each value, giving tied values the average of
+// their ranks.
+func ranks(values []int) []float64 {
+	order := make([]int, len(values))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(i, j int) int {
+		return cmp.Compare(values[i], values[j])
+	})
+	res := make([]float64, len(values))
+	for start := 0; start < len(order); {
+		end := start
+		for end < len(order) && values[order[end]] == values[order[start]] {
+			end++
+		}
+		rank := float64(start+end+1) / 2
+		for _, i := range order[start:end] {
+			res[i] = rank
+		}
+		start = end
+	}
+	return res
+}
+
+// spearman is the Pearson correlation of the ranks of the values, or 0 when
+// either has no spread.
+func spearman(x, y []int) float64 {
+	rx, ry := ranks(x), ranks(y)
+	var meanX, meanY float64
+	for i := range rx {
+		meanX += rx[i]
+		meanY += ry[i]
+	}
+	meanX /= float64(len(rx))
+	meanY /= float64(len(ry))
+	var cov, varX, varY float64
+	for i := range rx {
+		cov += (rx[i] - meanX) * (ry[i] - meanY)
+		varX += (rx[i] - meanX) * (rx[i] - meanX)
+		varY += (ry[i] - meanY) * (ry[i] - meanY)
+	}
+	if varX == 0 || varY == 0 {
+		return 0
+	}
+	return cov / math.Sqrt(varX*varY)
+}
+
+// NewSeedingMetrics measures the seeding of the event from the seeds and
+// placements of its entrants.
+func NewSeedingMetrics(upsetThread *UpsetThread) SeedingMetrics {
+	entrants := seededEntrants(upsetThread)
+	res := SeedingMetrics{Entrants: len(entrants)}
+	if len(entrants) == 0 {
+		return res
+	}
+	seeds, placements := make([]int, len(entrants)), make([]int, len(entrants))
+	tierErrors := 0
+	for i, entrant := range entrants {
+		seeds[i], placements[i] = entrant.seed, entrant.placement
+		tierErrors += max(getTableIdx(entrant.seed)-getTableIdx(entrant.placement), getTableIdx(entrant.placement)-getTableIdx(entrant.seed))
+	}
+	res.MeanTierError = float64(tierErrors) / float64(len(entrants))
+	res.Spearman = spearman(seeds, placements)
+	return res
+}
+
+// NewSeedingReport measures the seeding of the event and compares it with the
+// other events. Events with fewer than two placed entrants are left out of
+// the benchmark.
+func NewSeedingReport(upsetThread *UpsetThread, others []*UpsetThread) *SeedingReport {
+	res := &SeedingReport{
+		Title:   cmp.Or(upsetThread.Title, upsetThread.Slug),
+		Slug:    upsetThread.Slug,
+		Metrics: NewSeedingMetrics(upsetThread),
+		Buckets: UpsetFactorBuckets,
+		Phases:  []PhaseUpsets{},
+	}
+	for _, item := range rankedUpsets(upsetThread) {
+		phase := cmp.Or(item.PhaseName, "Bracket")
+		i := slices.IndexFunc(res.Phases, func(p PhaseUpsets) bool { return p.Phase == phase })
+		if i < 0 {
+			i = len(res.Phases)
+			res.Phases = append(res.Phases, PhaseUpsets{Phase: phase, Counts: make([]int, len(UpsetFactorBuckets))})
+		}
+		res.Phases[i].Counts[upsetFactorBucket(item.UpsetFactor)]++
+	}
+	slices.SortFunc(res.Phases, func(i, j PhaseUpsets) int {
+		return cmp.Compare(i.Phase, j.Phase)
+	})
+	for _, other := range others {
+		metrics := NewSeedingMetrics(other)
+		if metrics.Entrants < 2 {
+			continue
+		}
+		res.Benchmark.Events++
+		res.Benchmark.MeanTierError += metrics.MeanTierError
+		res.Benchmark.Spearman += metrics.Spearman
+		if metrics.Spearman < res.Metrics.Spearman {
+			res.Benchmark.BetterThan++
+		}
+	}
+	if events := float64(res.Benchmark.Events); events > 0 {
+		res.Benchmark.MeanTierError /= events
+		res.Benchmark.Spearman /= events
+		res.Benchmark.BetterThan /= events
+	}
+	return res
+}
@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestRanks(t *testing.T) {
+	if res := ranks([]int{3, 1, 2, 2}); !slices.Equal(res, []float64{4, 1, 2.5, 2.5}) {
+		t.Errorf("Expected tied values to share their average rank, got %v", res)
+	}
+}
+
+func TestNewSeedingReport(t *testing.T) {
+	upsetThread := &UpsetThread{
+		Slug:  "tournament/a/event/singles",
+		Title: "A",
+		Winners: []UpsetThreadItem{
+			{Id: "1", WinnersSeed: 1, WinnersPlacement: 1, LosersSeed: 4, LosersPlacement: 4, CompletedAt: 10},
+			{Id: "2", WinnersSeed: 3, WinnersPlacement: 2, LosersSeed: 2, LosersPlacement: 3, UpsetFactor: 1, CompletedAt: 20, PhaseName: "Top 8"},
+		},
+		Pools: []UpsetThreadItem{
+			{Id: "3", WinnersSeed: 9, LosersSeed: 2, UpsetFactor: 7, CompletedAt: 5},
+		},
+		DQs: []UpsetThreadItem{
+			{Id: "4", WinnersSeed: 12, LosersSeed: 1, UpsetFactor: 8, CompletedAt: 6, Outcome: OutcomeDQ},
+		},
+	}
+	toSeed := &UpsetThread{Slug: "tournament/b/event/singles", Winners: []UpsetThreadItem{
+		{Id: "1", WinnersSeed: 1, WinnersPlacement: 1, LosersSeed: 2, LosersPlacement: 2, CompletedAt: 10},
+	}}
+	tooSmall := &UpsetThread{Slug: "tournament/c/event/singles", Winners: []UpsetThreadItem{
+		{Id: "1", WinnersSeed: 1, WinnersPlacement: 1, LosersSeed: 2, CompletedAt: 10},
+	}}
+	res := NewSeedingReport(upsetThread, []*UpsetThread{toSeed, tooSmall})
+	if res.Metrics.Entrants != 4 || res.Metrics.MeanTierError != 0.5 || math.Abs(res.Metrics.Spearman-0.8) > 1e-9 {
+		t.Errorf("Expected 4 entrants, a mean tier error of 0.5 and a correlation of 0.8, got %+v", res.Metrics)
+	}
+	if len(res.Phases) != 2 ||
+		res.Phases[0].Phase != "Bracket" || !slices.Equal(res.Phases[0].Counts, []int{0, 0, 0, 1}) ||
+		res.Phases[1].Phase != "Top 8" || !slices.Equal(res.Phases[1].Counts, []int{1, 0, 0, 0}) {
+		t.Errorf("Expected an upset of each phase without the DQ, got %+v", res.Phases)
+	}
+	if res.Benchmark != (SeedingBenchmark{Events: 1, MeanTierError: 0, Spearman: 1, BetterThan: 0}) {
+		t.Errorf("Expected a benchmark of the event that went to seed, got %+v", res.Benchmark)
+	}
+}
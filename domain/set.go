@@ -1,10 +1,7 @@
 package domain
 
 import (
-	"log"
-	"slices"
 	"sort"
-	"strconv"
 	"strings"
 )
 
@@ -49,6 +46,20 @@ type Entrant struct {
 	InitialSeed int
 	Placement   int
 	IsFinal     bool
+	// PlayerIds are the start.gg players of the entrant, one per member of
+	// a team.
+	PlayerIds []int
+	// Prefix is the sponsor prefix and Tag the gamer tag of a single player
+	// entrant. Tag is the whole name of a team.
+	Prefix, Tag string
+	// Score is the number of games the entrant won in the set, when start.gg
+	// reports it, and -1 when the entrant was disqualified. ScoreLabel is "W"
+	// or "L" when the set was reported without games.
+	Score      *int
+	ScoreLabel string
+	// Record is the entrant's wins and losses in the pool of a pool stage
+	// set, such as 1-4.
+	Record string
 }
 
 type Character struct {
@@ -65,6 +76,10 @@ type Game struct {
 	Id         int
 	WinnerId   int
 	Selections []Selection
+	// Scores are what each entrant had left at the end of the game, such as
+	// stocks, keyed by entrant ID. They are only set when start.gg reports
+	// them.
+	Scores map[int]int
 }
 
 func initSlots(winnerId int, entrants []Entrant) (Entrant, Entrant) {
@@ -79,59 +94,6 @@ func initUpsetFactor(winnerSeed, loserSeed int) int {
 	return upsetFactorTable.GetUpsetFactor(winnerSeed, loserSeed)
 }
 
-func reverseString(s string) string {
-	runes := []rune(s)
-	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
-		runes[i], runes[j] = runes[j], runes[i]
-	}
-	return string(runes)
-}
-
-func initScore(games *[]Game, displayScore string, winner, loser Entrant, totalGames int) *string {
-	if displayScore == "DQ" {
-		return &displayScore
-	}
-	var scoresFromGames *string
-	scoreFromDisplayScore := displayScore
-	scoreFromDisplayScore = strings.Replace(scoreFromDisplayScore, winner.Name, "", 1)
-	scoreFromDisplayScore = strings.Replace(scoreFromDisplayScore, loser.Name, "", 1)
-	scoreFromDisplayScore = strings.Replace(scoreFromDisplayScore, " ", "", -1)
-	if slices.Contains([]string{"0-2", "0-3", "1-2", "1-3", "2-3"}, scoreFromDisplayScore) {
-		scoreFromDisplayScore = reverseString(scoreFromDisplayScore)
-	}
-	if games != nil {
-		winnerScore, loserScore := 0, 0
-		for _, game := range *games {
-			if game.WinnerId == winner.Id {
-				winnerScore++
-			} else {
-				loserScore++
-			}
-			s := strconv.Itoa(winnerScore) + "-" + strconv.Itoa(loserScore)
-			scoresFromGames = &s
-		}
-	}
-	if len(scoreFromDisplayScore) > 0 && scoresFromGames != nil && len(*scoresFromGames) > 0 {
-		numFromDisplayScore, err := strconv.Atoi(string(scoreFromDisplayScore[0]))
-		if err != nil {
-			log.Fatalf("Error while converting display score to int. e=%s\n", err)
-		}
-		gameScore := *scoresFromGames
-		numFromGameScore, err := strconv.Atoi(string(gameScore[0]))
-		if err != nil {
-			log.Fatalf("Error while converting game score to int. e=%s\n", err)
-		}
-		if numFromDisplayScore > numFromGameScore {
-			return &scoreFromDisplayScore
-		}
-		return scoresFromGames
-	}
-	if scoresFromGames != nil {
-		return scoresFromGames
-	}
-	return &scoreFromDisplayScore
-}
-
 type Set struct {
 	Id              string
 	DisplayScore    string
@@ -139,18 +101,43 @@ type Set struct {
 	Round           int
 	LosersPlacement int
 	TotalGames      int
+	GamesType       int
 	Games           *[]Game
 	CompletedAt     int
 	Winner          Entrant
 	Loser           Entrant
 	UpsetFactor     int
 	Score           *string
+	Outcome         Outcome
+	VodUrl          string
+	StreamName      string
+	StreamSource    string
+	GrandFinal      GrandFinal
+	PhaseGroupId    int
+	BracketType     BracketType
+	PhaseId         int
+	PhaseName       string
+	PoolIdentifier  string
+	// FinalPhase is set for the sets of the event's last phase, where its
+	// grand finals are played.
+	FinalPhase bool
+	// Notable is set for sets that are notable under the rules of their
+	// game, whichever section of the thread they are listed in.
+	Notable bool
+	// GameSlug is the start.gg slug of the event's game, such as
+	// game/ultimate.
+	GameSlug string
 }
 
 func NewSet(identifier string, displayScore string, fullRoundText *string, totalGames int, roundNum int, losersPlacement int, winnerId int, entrants []Entrant, games *[]Game, completedAt int) *Set {
 	winner, loser := initSlots(winnerId, entrants)
-	upsetFactor := initUpsetFactor(winner.InitialSeed, loser.InitialSeed)
-	score := initScore(games, displayScore, winner, loser, totalGames)
+	hasWinner := winnerId != 0 && (winnerId == winner.Id || winnerId == loser.Id)
+	score, outcome := initScore(games, displayScore, winner, loser, hasWinner)
+	upsetFactor := 0
+	// Neither entrant of a tie upset the other.
+	if outcome != OutcomeTie {
+		upsetFactor = initUpsetFactor(winner.InitialSeed, loser.InitialSeed)
+	}
 	return &Set{
 		Id:              identifier,
 		DisplayScore:    displayScore,
@@ -164,26 +151,31 @@ func NewSet(identifier string, displayScore string, fullRoundText *string, total
 		Loser:           loser,
 		UpsetFactor:     upsetFactor,
 		Score:           score,
+		Outcome:         outcome,
 	}
 }
 
+// IsPoolStage reports whether the set was played in round robin pools or a
+// Swiss stage, where neither entrant is knocked out.
+func (s *Set) IsPoolStage() bool {
+	return s.BracketType.IsPoolStage()
+}
+
 func (s *Set) IsWinnersBracket() bool {
-	return s.Round > 0
+	return s.Round > 0 && !s.IsPoolStage()
 }
 
-func (s *Set) IsDQ() bool {
-	return s.DisplayScore == "DQ"
+func (s *Set) IsLosersBracket() bool {
+	return s.Round <= 0 && !s.IsPoolStage()
 }
 
-func (s *Set) IsDQAndOut() bool {
-	return !s.IsWinnersBracket() && s.IsDQ()
+// IsDQ reports whether the loser was disqualified, before or during the set.
+func (s *Set) IsDQ() bool {
+	return s.Outcome == OutcomeDQ || s.Outcome == OutcomeForfeit
 }
 
-func (s *Set) IsNotable() bool {
-	if s.Score == nil {
-		return false
-	}
-	return slices.Contains([]string{"3-2", "2-1"}, *s.Score)
+func (s *Set) IsDQAndOut() bool {
+	return s.IsLosersBracket() && s.IsDQ()
 }
 
 func (s *Set) GetCharacterSelections(entrantId int) string {
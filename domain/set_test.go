@@ -6,8 +6,9 @@ import (
 	"time"
 )
 
-var e1 Entrant = Entrant{12394650, "LG | Tweek", 3, 9, true}
-var e2 Entrant = Entrant{12687800, "Zomba", 20, 8, false}
+var e1 Entrant = Entrant{Id: 12394650, Name: "LG | Tweek", InitialSeed: 3, Placement: 9, IsFinal: true}
+var e2 Entrant = Entrant{Id: 12687800, Name: "Zomba", InitialSeed: 20, Placement: 8, IsFinal: false}
+var one, three = 1, 3
 var s1 []Selection = []Selection{{e1, &Character{1279, "Diddy Kong"}}, {e2, &Character{1323, "R.O.B."}}}
 var s2 []Selection = []Selection{{e1, &Character{1777, "Sephiroth"}}, {e2, &Character{1323, "R.O.B."}}}
 
@@ -66,7 +67,7 @@ var setTestCases []setTestCase = []setTestCase{
 			9,
 			12687800,
 			[]Entrant{e1, e2},
-			&[]Game{{16955184, 12687800, s1}, {16955185, 12687800, s2}, {16955186, 12687800, s2}},
+			&[]Game{{16955184, 12687800, s1, nil}, {16955185, 12687800, s2, nil}, {16955186, 12687800, s2, nil}},
 			int(time.Now().UnixMilli()),
 		),
 		"3-0",
@@ -85,7 +86,7 @@ var setTestCases []setTestCase = []setTestCase{
 			9,
 			12687800,
 			[]Entrant{e1, e2},
-			&[]Game{{16955184, 12687800, s1}, {16955185, 12687800, s1}, {16955186, 12394650, s2}, {16955187, 12687800, s1}},
+			&[]Game{{16955184, 12687800, s1, nil}, {16955185, 12687800, s1, nil}, {16955186, 12394650, s2, nil}, {16955187, 12687800, s1, nil}},
 			int(time.Now().UnixMilli()),
 		),
 		"3-1",
@@ -104,7 +105,7 @@ var setTestCases []setTestCase = []setTestCase{
 			9,
 			12687800,
 			[]Entrant{e1, e2},
-			&[]Game{{16955184, 12687800, s1}, {16955185, 12687800, s1}, {16955186, 12394650, s2}, {16955187, 12687800, s1}},
+			&[]Game{{16955184, 12687800, s1, nil}, {16955185, 12687800, s1, nil}, {16955186, 12394650, s2, nil}, {16955187, 12687800, s1, nil}},
 			int(time.Now().UnixMilli()),
 		),
 		"DQ",
@@ -142,7 +143,7 @@ var setTestCases []setTestCase = []setTestCase{
 			9,
 			12687800,
 			[]Entrant{e1, e2},
-			&[]Game{{16955184, 12687800, s1}, {16955185, 12687800, s1}, {169551846, 12394650, s2}},
+			&[]Game{{16955184, 12687800, s1, nil}, {16955185, 12687800, s1, nil}, {169551846, 12394650, s2, nil}},
 			int(time.Now().UnixMilli()),
 		),
 		"3-0",
@@ -170,6 +171,28 @@ var setTestCases []setTestCase = []setTestCase{
 		false,
 		true,
 	},
+	{
+		NewSet(
+			"60482458",
+			"Player 3 1 - FaZe | Sparg0 3",
+			nil,
+			5,
+			2,
+			0,
+			12687801,
+			[]Entrant{
+				{Id: 12394651, Name: "Player 3", InitialSeed: 5, Score: &one},
+				{Id: 12687801, Name: "FaZe | Sparg0", InitialSeed: 1, Score: &three},
+			},
+			nil,
+			int(time.Now().UnixMilli()),
+		),
+		"3-1",
+		"",
+		"",
+		false,
+		false,
+	},
 }
 
 func TestSet(t *testing.T) {
@@ -195,7 +218,7 @@ func TestSet(t *testing.T) {
 			if resultIsDQAndOut != expectedIsDQAndOut {
 				t.Errorf("DQ and out failed. Result %t, expected %t", resultIsDQAndOut, expectedIsDQAndOut)
 			}
-			resultIsNotable := test.set.IsNotable()
+			resultIsNotable := test.set.IsNotable(DefaultNotableRules)
 			expectedIsNotable := test.expectedIsNotable
 			if resultIsNotable != expectedIsNotable {
 				t.Errorf("Notable failed. Result %t, expected %t", resultIsNotable, expectedIsNotable)
@@ -0,0 +1,322 @@
+package domain
+
+import (
+	"cmp"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"slices"
+)
+
+const (
+	// SimulationModelSeed gives the better seed a chance of winning that grows
+	// with the number of seed tiers between the entrants.
+	SimulationModelSeed = "seed"
+	// SimulationModelRating uses the players' ratings, falling back to their
+	// seeds when either is not rated yet.
+	SimulationModelRating = "rating"
+	// SeedTierGap is the rating difference, in Elo points, between adjacent
+	// seed tiers in the seed model.
+	SeedTierGap = 75
+	// DefaultSimulations and MaxSimulations bound how many times a bracket is
+	// played out.
+	DefaultSimulations = 1000
+	MaxSimulations     = 10000
+)
+
+var (
+	// SimulationModels are the accepted values of SimulationOptions.Model.
+	SimulationModels = []string{SimulationModelSeed, SimulationModelRating}
+
+	ErrorUnknownSimulationModel = errors.New("model must be seed or rating")
+	ErrorInvalidSimulations     = errors.New("simulations must be between 1 and 10000")
+)
+
+// BracketSlot is one side of a set. It is filled by the entrant seeded into
+// it, or by the winner, for PrereqPlacement 1, or the loser, for 2, of the set
+// PrereqSetId. A slot that is none of these is filled once the entrant is
+// known, such as in a phase that was not seeded yet.
+type BracketSlot struct {
+	EntrantId       int
+	PrereqSetId     string
+	PrereqPlacement int
+	// Bye is set for slots that are never filled.
+	Bye bool
+}
+
+type BracketSet struct {
+	Id    string
+	Slots []BracketSlot
+}
+
+// Bracket is how entrants advance through the sets of an event, in the
+// order the sets are played.
+type Bracket struct {
+	Entrants map[int]Entrant
+	Sets     []BracketSet
+}
+
+// Key identifies the entrant across events for ratings, as
+// UpsetThreadItem.WinnersKey does for the winner of a set.
+func (e Entrant) Key() string {
+	return playerKey(e.Name, e.Tag, e.PlayerIds)
+}
+
+type SimulationOptions struct {
+	Model       string
+	Simulations int
+	// Ratings are keyed by Entrant.Key, for the rating model.
+	Ratings map[string]Rating
+}
+
+func (o SimulationOptions) Validate() error {
+	if !slices.Contains(SimulationModels, o.Model) {
+		return ErrorUnknownSimulationModel
+	}
+	if o.Simulations < 1 || o.Simulations > MaxSimulations {
+		return ErrorInvalidSimulations
+	}
+	return nil
+}
+
+// SeedWinProbability is the chance that the entrant seeded seed beats the one
+// seeded opponentSeed in the seed model.
+func SeedWinProbability(seed, opponentSeed int) float64 {
+	difference := float64(getTableIdx(opponentSeed)-getTableIdx(seed)) * SeedTierGap
+	return 1 / (1 + math.Pow(10, -difference/400))
+}
+
+func (o SimulationOptions) winProbability(entrant, opponent Entrant) float64 {
+	if o.Model == SimulationModelRating {
+		rating, ok := o.Ratings[entrant.Key()]
+		opponentRating, opponentOk := o.Ratings[opponent.Key()]
+		if ok && opponentOk {
+			return WinProbability(rating, opponentRating)
+		}
+	}
+	return SeedWinProbability(entrant.InitialSeed, opponent.InitialSeed)
+}
+
+// UpsetFactorExpectation compares the upsets of an upset factor expected from
+// the simulations with those of the event.
+type UpsetFactorExpectation struct {
+	UpsetFactor int `json:"upsetFactor"`
+	// Expected is the average number of upsets of exactly this factor in a
+	// simulated event, and Actual the number so far.
+	Expected float64 `json:"expected"`
+	Actual   int     `json:"actual"`
+	// ExpectedAtLeast is the average number of upsets of at least this
+	// factor in a simulated event, with Low and High the 10th and 90th
+	// percentiles.
+	ExpectedAtLeast float64 `json:"expectedAtLeast"`
+	Low             int     `json:"low"`
+	High            int     `json:"high"`
+	ActualAtLeast   int     `json:"actualAtLeast"`
+	// ExpectedSoFar is the number of upsets of at least this factor expected
+	// from the sets played so far, given who played them. An event with many
+	// more actual upsets is unusually chaotic.
+	ExpectedSoFar float64 `json:"expectedSoFar"`
+}
+
+// SimulationReport is the outcome of playing out a bracket many times.
+type SimulationReport struct {
+	Title       string `json:"title"`
+	Slug        string `json:"slug"`
+	Model       string `json:"model"`
+	Simulations int    `json:"simulations"`
+	// Sets counts the sets of the bracket and Simulated those that could be
+	// played out, leaving out later phases that were not seeded yet.
+	Sets      int                      `json:"sets"`
+	Simulated int                      `json:"simulated"`
+	Played    int                      `json:"played"`
+	Factors   []UpsetFactorExpectation `json:"factors"`
+}
+
+type simulatedSet struct {
+	// slots holds the index of the bracket set feeding each slot, or -1 when
+	// the slot is filled by its entrant or empty.
+	slots    [2]int
+	slotData [2]BracketSlot
+	// reset is set for a bracket reset, which is only played when the winner
+	// of the grand final had lost a set before.
+	reset bool
+}
+
+// simulatedSets returns the sets that can be played out, in an order where
+// each set comes after the sets feeding it.
+func simulatedSets(bracket *Bracket) []simulatedSet {
+	index := make(map[string]int, len(bracket.Sets))
+	for i, set := range bracket.Sets {
+		index[set.Id] = i
+	}
+	const (
+		unvisited = iota
+		visiting
+		resolved
+		unresolved
+	)
+	state := make([]int, len(bracket.Sets))
+	order := make([]int, 0, len(bracket.Sets))
+	var visit func(i int) bool
+	visit = func(i int) bool {
+		switch state[i] {
+		case resolved:
+			return true
+		case unresolved, visiting:
+			return false
+		}
+		state[i] = visiting
+		ok := len(bracket.Sets[i].Slots) == 2
+		for _, slot := range bracket.Sets[i].Slots {
+			if slot.PrereqSetId != "" {
+				prereq, found := index[slot.PrereqSetId]
+				ok = ok && found && visit(prereq)
+			} else if slot.EntrantId == 0 && !slot.Bye {
+				ok = false
+			}
+		}
+		if !ok {
+			state[i] = unresolved
+			return false
+		}
+		state[i] = resolved
+		order = append(order, i)
+		return true
+	}
+	for i := range bracket.Sets {
+		visit(i)
+	}
+	position := make(map[int]int, len(order))
+	for p, i := range order {
+		position[i] = p
+	}
+	res := make([]simulatedSet, len(order))
+	for p, i := range order {
+		set := bracket.Sets[i]
+		res[p].slots = [2]int{-1, -1}
+		for s, slot := range set.Slots {
+			res[p].slotData[s] = slot
+			if slot.PrereqSetId != "" {
+				res[p].slots[s] = position[index[slot.PrereqSetId]]
+			}
+		}
+		res[p].reset = set.Slots[0].PrereqSetId != "" && set.Slots[0].PrereqSetId == set.Slots[1].PrereqSetId
+	}
+	return res
+}
+
+// simulate plays out the sets once and counts the upsets by upset factor.
+func simulate(sets []simulatedSet, bracket *Bracket, options SimulationOptions, r *rand.Rand, counts []int) []int {
+	results := make([][2]int, len(sets))
+	losses := make(map[int]int)
+	for i, set := range sets {
+		var entrants [2]int
+		for s, slot := range set.slotData {
+			if set.slots[s] >= 0 {
+				entrants[s] = results[set.slots[s]][slot.PrereqPlacement-1]
+			} else if !slot.Bye {
+				entrants[s] = slot.EntrantId
+			}
+		}
+		if set.reset && losses[results[set.slots[0]][0]] == 0 {
+			continue
+		}
+		if entrants[0] == 0 || entrants[1] == 0 {
+			results[i] = [2]int{max(entrants[0], entrants[1]), 0}
+			continue
+		}
+		a, b := bracket.Entrants[entrants[0]], bracket.Entrants[entrants[1]]
+		if r.Float64() >= options.winProbability(a, b) {
+			a, b = b, a
+		}
+		results[i] = [2]int{a.Id, b.Id}
+		losses[b.Id]++
+		if upsetFactor := upsetFactorTable.GetUpsetFactor(a.InitialSeed, b.InitialSeed); upsetFactor > 0 && a.InitialSeed > 0 && b.InitialSeed > 0 {
+			for len(counts) <= upsetFactor {
+				counts = append(counts, 0)
+			}
+			counts[upsetFactor]++
+		}
+	}
+	return counts
+}
+
+// percentile returns the value below which the share p of the sorted values
+// fall.
+func percentile(sorted []int, p float64) int {
+	if len(sorted) == 0 {
+		return 0
+	}
+	return sorted[min(int(p*float64(len(sorted))), len(sorted)-1)]
+}
+
+// NewSimulationReport plays out the bracket options.Simulations times and
+// compares the upsets of each upset factor with those of the upset thread so
+// far.
+func NewSimulationReport(upsetThread *UpsetThread, bracket *Bracket, options SimulationOptions, r *rand.Rand) (*SimulationReport, error) {
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+	sets := simulatedSets(bracket)
+	res := &SimulationReport{
+		Title:       cmp.Or(upsetThread.Title, upsetThread.Slug),
+		Slug:        upsetThread.Slug,
+		Model:       options.Model,
+		Simulations: options.Simulations,
+		Sets:        len(bracket.Sets),
+		Simulated:   len(sets),
+		Factors:     []UpsetFactorExpectation{},
+	}
+	runs := make([][]int, options.Simulations)
+	maxUpsetFactor := 0
+	for i := range runs {
+		runs[i] = simulate(sets, bracket, options, r, nil)
+		maxUpsetFactor = max(maxUpsetFactor, len(runs[i])-1)
+	}
+	actual := make(map[int]int)
+	for _, item := range rankedUpsets(upsetThread) {
+		actual[item.UpsetFactor]++
+		maxUpsetFactor = max(maxUpsetFactor, item.UpsetFactor)
+	}
+	soFar := make(map[int]float64)
+	for _, item := range playedSets(upsetThread) {
+		res.Played++
+		better, worse := min(item.WinnersSeed, item.LosersSeed), max(item.WinnersSeed, item.LosersSeed)
+		upsetFactor := upsetFactorTable.GetUpsetFactor(worse, better)
+		if better <= 0 || upsetFactor <= 0 {
+			continue
+		}
+		p := SeedWinProbability(worse, better)
+		if options.Model == SimulationModelRating && item.WinProbability > 0 {
+			p = item.WinProbability
+			if item.WinnersSeed == better {
+				p = 1 - p
+			}
+		}
+		soFar[upsetFactor] += p
+	}
+	for upsetFactor := 1; upsetFactor <= maxUpsetFactor; upsetFactor++ {
+		expectation := UpsetFactorExpectation{UpsetFactor: upsetFactor}
+		atLeast := make([]int, len(runs))
+		for i, counts := range runs {
+			for f := upsetFactor; f < len(counts); f++ {
+				atLeast[i] += counts[f]
+			}
+			if upsetFactor < len(counts) {
+				expectation.Expected += float64(counts[upsetFactor])
+			}
+			expectation.ExpectedAtLeast += float64(atLeast[i])
+		}
+		expectation.Expected /= float64(len(runs))
+		expectation.ExpectedAtLeast /= float64(len(runs))
+		slices.Sort(atLeast)
+		expectation.Low, expectation.High = percentile(atLeast, 0.1), percentile(atLeast, 0.9)
+		expectation.Actual = actual[upsetFactor]
+		for f := upsetFactor; f <= maxUpsetFactor; f++ {
+			expectation.ActualAtLeast += actual[f]
+			expectation.ExpectedSoFar += soFar[f]
+		}
+		res.Factors = append(res.Factors, expectation)
+	}
+	return res, nil
+}
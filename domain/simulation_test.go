@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+)
+
+func TestNewSimulationReport(t *testing.T) {
+	bracket := &Bracket{
+		Entrants: map[int]Entrant{
+			10: {Id: 10, Name: "Sonix", InitialSeed: 1},
+			20: {Id: 20, Name: "Zomba", InitialSeed: 16},
+		},
+		Sets: []BracketSet{
+			{Id: "final", Slots: []BracketSlot{{PrereqSetId: "first", PrereqPlacement: 1}, {}}},
+			{Id: "first", Slots: []BracketSlot{{EntrantId: 10}, {EntrantId: 20}}},
+			{Id: "next", Slots: []BracketSlot{{PrereqSetId: "first", PrereqPlacement: 2}, {Bye: true}}},
+		},
+	}
+	score := "3-2"
+	upsetThread := &UpsetThread{Slug: "tournament/a/event/singles", Winners: []UpsetThreadItem{
+		{Id: "1", WinnersSeed: 16, LosersSeed: 1, Score: &score, UpsetFactor: upsetFactorTable.GetUpsetFactor(16, 1), CompletedAt: 10},
+	}}
+	upsetFactor := upsetFactorTable.GetUpsetFactor(16, 1)
+	res, err := NewSimulationReport(upsetThread, bracket, SimulationOptions{Model: SimulationModelSeed, Simulations: 10000}, rand.New(rand.NewPCG(1, 2)))
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if res.Sets != 3 || res.Simulated != 2 || res.Played != 1 || len(res.Factors) != upsetFactor {
+		t.Fatalf("Expected the first and next sets to be simulated up to upset factor %d, got %+v", upsetFactor, res)
+	}
+	upsetChance := SeedWinProbability(16, 1)
+	factor := res.Factors[upsetFactor-1]
+	if math.Abs(factor.Expected-upsetChance) > 0.02 || factor.Actual != 1 || math.Abs(factor.ExpectedSoFar-upsetChance) > 1e-9 {
+		t.Errorf("Expected %.3f upsets of factor %d and 1 so far, got %+v", upsetChance, upsetFactor, factor)
+	}
+	if first := res.Factors[0]; first.ActualAtLeast != 1 || first.ExpectedAtLeast != factor.Expected || first.Low != 0 || first.High != 0 {
+		t.Errorf("Expected every upset to count from upset factor 1, got %+v", first)
+	}
+}
+
+func TestSimulatedSetsReset(t *testing.T) {
+	sets := simulatedSets(&Bracket{Sets: []BracketSet{
+		{Id: "reset", Slots: []BracketSlot{{PrereqSetId: "final", PrereqPlacement: 1}, {PrereqSetId: "final", PrereqPlacement: 2}}},
+		{Id: "final", Slots: []BracketSlot{{EntrantId: 1}, {EntrantId: 2}}},
+	}})
+	if len(sets) != 2 || sets[0].reset || !sets[1].reset || sets[1].slots != [2]int{0, 0} {
+		t.Errorf("Expected the final before its reset, got %+v", sets)
+	}
+}
+
+func TestSimulationOptionsValidate(t *testing.T) {
+	if err := (SimulationOptions{Model: "elo", Simulations: 1}).Validate(); err != ErrorUnknownSimulationModel {
+		t.Errorf("Expected %s, got %v", ErrorUnknownSimulationModel, err)
+	}
+	if err := (SimulationOptions{Model: SimulationModelRating, Simulations: MaxSimulations + 1}).Validate(); err != ErrorInvalidSimulations {
+		t.Errorf("Expected %s, got %v", ErrorInvalidSimulations, err)
+	}
+}
@@ -4,34 +4,86 @@ type UpsetThreadItem struct {
 	Id, WinnersName, WinnersCharacters                    string
 	WinnersSeed                                           int
 	Score                                                 *string
+	Outcome                                               Outcome
 	LosersName, LosersCharacters                          string
 	IsWinnersBracket                                      bool
 	LosersSeed, LosersPlacement, UpsetFactor, CompletedAt int
 	Category                                              string
+	VodUrl, StreamName, StreamSource                      string
+	WinnersPlayerIds, LosersPlayerIds                     []int
+	WinnersPrefix, WinnersTag, LosersPrefix, LosersTag    string
+	GrandFinal                                            GrandFinal
+	BracketType                                           BracketType
+	WinnersRecord, LosersRecord                           string
+	PhaseName, PoolIdentifier                             string
+	// WinnersPlacement is the winner's placement in the event, as
+	// LosersPlacement is the loser's.
+	WinnersPlacement int
+	// WinProbability is the winner's chance of winning the set according to
+	// the players' ratings before it, or 0 when the set was not rated.
+	WinProbability float64
+	// HeadToHead is the winner's record against the loser before the set,
+	// or nil when it is not known.
+	HeadToHead *HeadToHead
+	// Notable is set when the set is notable under the rules of its game,
+	// even if it is listed as an upset rather than among the notables.
+	Notable bool
+}
+
+// PhaseLabel names where the set was played, such as "Top 64" or "Pools
+// A12".
+func (i UpsetThreadItem) PhaseLabel() string {
+	return PhaseLabel(i.PhaseName, i.PoolIdentifier)
 }
 
 type UpsetThread struct {
-	Title    string
-	Slug     string
-	Winners  []UpsetThreadItem
-	Losers   []UpsetThreadItem
+	Title      string
+	Slug       string
+	Timezone   string
+	TimeFormat string
+	// LastUpdatedAt is when the event was last polled, in Unix seconds, or 0
+	// when it is not known.
+	LastUpdatedAt int
+	// GrandFinals has the grand final and bracket reset, whether or not they
+	// were upsets.
+	GrandFinals []UpsetThreadItem
+	Winners     []UpsetThreadItem
+	Losers      []UpsetThreadItem
+	// Pools has the upsets of round robin pools and Swiss stages.
+	Pools    []UpsetThreadItem
 	Notables []UpsetThreadItem
 	DQs      []UpsetThreadItem
 	Other    []UpsetThreadItem
+	// Watched has the sets of players on the event's watchlists, whichever
+	// section they are in.
+	Watched []UpsetThreadItem
 }
 
 type UpsetThreadItemDisplay struct {
-	Content string
-	Bold    bool
+	Content     string
+	Bold        bool
+	UpsetFactor int
+	// Url links to the set on start.gg and VodUrl to its recording, if any.
+	Url, VodUrl string
 }
 
 type UpsetThreadDisplay struct {
-	Host          string
-	Title         string
-	Slug          string
-	LastUpdatedAt string
-	Winners       []*UpsetThreadItemDisplay
-	Losers        []*UpsetThreadItemDisplay
-	Notables      []*UpsetThreadItemDisplay
-	DQs           []*UpsetThreadItemDisplay
+	Host                   string
+	Title                  string
+	Slug                   string
+	LastUpdatedAt          string
+	LastUpdatedAtTimestamp int64
+	GrandFinals            []*UpsetThreadItemDisplay
+	Winners                []*UpsetThreadItemDisplay
+	Losers                 []*UpsetThreadItemDisplay
+	Pools                  []*UpsetThreadItemDisplay
+	Notables               []*UpsetThreadItemDisplay
+	DQs                    []*UpsetThreadItemDisplay
+	Watched                []*UpsetThreadItemDisplay
+	// Characters highlights the characters that scored the most upsets, such
+	// as "Steve players scored 7 upsets".
+	Characters []string
+	// Part and Parts are set when the thread is split across a post and its
+	// comments. Part 1 is the post itself.
+	Part, Parts int
 }
@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Watchlist follows players across every tracked event, whether or not their
+// sets are upsets. Players are entrant names or gamer tags, matched
+// regardless of case, or start.gg player IDs.
+type Watchlist struct {
+	Id      string   `json:"id"`
+	Name    string   `json:"name"`
+	Players []string `json:"players"`
+	// WebhookUrl is sent a message for every set completed after CreatedAt.
+	WebhookUrl string `json:"webhookUrl"`
+	CreatedAt  int    `json:"createdAt"`
+}
+
+// isPlayer reports whether an entrant is the player, given as an entrant
+// name or gamer tag, matched regardless of case, or a start.gg player ID.
+func isPlayer(player, name, tag string, playerIds []int) bool {
+	player = strings.TrimSpace(player)
+	if player == "" {
+		return false
+	}
+	if strings.EqualFold(player, name) || (tag != "" && strings.EqualFold(player, tag)) {
+		return true
+	}
+	playerId, err := strconv.Atoi(player)
+	return err == nil && slices.Contains(playerIds, playerId)
+}
+
+func (w *Watchlist) watches(name, tag string, playerIds []int) bool {
+	for _, player := range w.Players {
+		if isPlayer(player, name, tag, playerIds) {
+			return true
+		}
+	}
+	return false
+}
+
+// Matches reports whether a completed set involves a watched player.
+func (w *Watchlist) Matches(item UpsetThreadItem) bool {
+	if item.CompletedAt == 0 {
+		return false
+	}
+	return w.watches(item.WinnersName, item.WinnersTag, item.WinnersPlayerIds) || w.watches(item.LosersName, item.LosersTag, item.LosersPlayerIds)
+}
+
+// WatchedItems returns the sets of the thread that match any of the
+// watchlists, most recently completed first.
+func WatchedItems(upsetThread *UpsetThread, watchlists []Watchlist) []UpsetThreadItem {
+	var res []UpsetThreadItem
+	for _, items := range [][]UpsetThreadItem{upsetThread.GrandFinals, upsetThread.Winners, upsetThread.Losers, upsetThread.Pools, upsetThread.Notables, upsetThread.DQs, upsetThread.Other} {
+		for _, item := range items {
+			for _, watchlist := range watchlists {
+				if watchlist.Matches(item) {
+					res = append(res, item)
+					break
+				}
+			}
+		}
+	}
+	slices.SortFunc(res, func(i, j UpsetThreadItem) int {
+		return cmp.Or(
+			cmp.Compare(j.CompletedAt, i.CompletedAt),
+			cmp.Compare(i.Id, j.Id),
+		)
+	})
+	return res
+}
+
+// WatchlistDisplay lists the watched sets of every tracked event, with the
+// sets of each event in its Watched section.
+type WatchlistDisplay struct {
+	Host   string
+	Id     string
+	Name   string
+	Events []*UpsetThreadDisplay
+}
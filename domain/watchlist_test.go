@@ -0,0 +1,41 @@
+package domain
+
+import "testing"
+
+type watchlistTestCase struct {
+	name     string
+	item     UpsetThreadItem
+	expected bool
+}
+
+var watchlist = Watchlist{Name: "Team", Players: []string{"lg | tweek", "1234", "mkleo"}}
+
+var watchlistTestCases = []watchlistTestCase{
+	{"Entrant name in any case", UpsetThreadItem{WinnersName: "LG | Tweek", LosersName: "Zomba", CompletedAt: 1}, true},
+	{"Gamer tag", UpsetThreadItem{WinnersName: "Zomba", LosersName: "T1 | MkLeo", LosersTag: "MkLeo", CompletedAt: 1}, true},
+	{"Player ID", UpsetThreadItem{WinnersName: "Zomba", LosersName: "Sparg0", LosersPlayerIds: []int{1234}, CompletedAt: 1}, true},
+	{"Other players", UpsetThreadItem{WinnersName: "Zomba", LosersName: "Sparg0", LosersPlayerIds: []int{12345}, CompletedAt: 1}, false},
+	{"Set not completed", UpsetThreadItem{WinnersName: "LG | Tweek", LosersName: "Zomba"}, false},
+}
+
+func TestWatchlistMatches(t *testing.T) {
+	for _, tc := range watchlistTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if res := watchlist.Matches(tc.item); res != tc.expected {
+				t.Errorf("Expected %v, got %v", tc.expected, res)
+			}
+		})
+	}
+}
+
+func TestWatchedItems(t *testing.T) {
+	upsetThread := &UpsetThread{
+		Winners: []UpsetThreadItem{{Id: "1", WinnersName: "LG | Tweek", CompletedAt: 10}},
+		Losers:  []UpsetThreadItem{{Id: "2", LosersName: "Zomba", CompletedAt: 30}},
+		Other:   []UpsetThreadItem{{Id: "3", LosersName: "LG | Tweek", CompletedAt: 20}},
+	}
+	res := WatchedItems(upsetThread, []Watchlist{watchlist})
+	if len(res) != 2 || res[0].Id != "3" || res[1].Id != "1" {
+		t.Errorf("Expected sets 3 and 1, got %v", res)
+	}
+}
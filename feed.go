@@ -0,0 +1,59 @@
+package main
+
+import (
+	"encoding/xml"
+	"gg/domain"
+	"gg/mapper"
+	"gg/service"
+	"log"
+	"net/http"
+	"time"
+)
+
+type FeedHandler struct {
+	tracker service.TrackerInterface
+}
+
+// handleFeeds registers an Atom feed per tracked event and one combining
+// every tracked event.
+func handleFeeds(tracker service.TrackerInterface) {
+	h := &FeedHandler{tracker: tracker}
+	http.HandleFunc("GET /feed.atom", h.combinedFeed)
+	http.HandleFunc("GET /event/tournament/{tournament}/event/{event}/feed.atom", h.eventFeed)
+}
+
+func writeFeed(w http.ResponseWriter, r *http.Request, feed *domain.Feed) {
+	feed.Links = append(feed.Links, domain.FeedLink{Href: "http://" + r.Host + r.URL.Path, Rel: "self"})
+	w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+	w.Write([]byte(xml.Header))
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(feed); err != nil {
+		log.Printf("Error while encoding feed. e=%s\n", err)
+	}
+}
+
+func (h *FeedHandler) eventFeed(w http.ResponseWriter, r *http.Request) {
+	upsetThread, err := h.tracker.GetUpsetThread(eventSlug(r))
+	if err != nil {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+	feed := mapper.ToFeed("https://www.start.gg/"+upsetThread.Slug, upsetThread.Title, []*domain.UpsetThread{upsetThread}, time.Now())
+	feed.Links = append(feed.Links, domain.FeedLink{Href: "http://" + r.Host + "/event/" + upsetThread.Slug})
+	writeFeed(w, r, feed)
+}
+
+func (h *FeedHandler) combinedFeed(w http.ResponseWriter, r *http.Request) {
+	var upsetThreads []*domain.UpsetThread
+	for _, event := range h.tracker.GetEvents() {
+		upsetThread, err := h.tracker.GetUpsetThread(event.Slug)
+		if err != nil {
+			continue
+		}
+		upsetThreads = append(upsetThreads, upsetThread)
+	}
+	feed := mapper.ToFeed("urn:gg:events", "Upsets", upsetThreads, time.Now())
+	feed.Links = append(feed.Links, domain.FeedLink{Href: "http://" + r.Host + "/"})
+	writeFeed(w, r, feed)
+}
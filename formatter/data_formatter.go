@@ -0,0 +1,225 @@
+package formatter
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"gg/domain"
+	"strconv"
+	"strings"
+)
+
+type CSVFormatter struct{}
+
+func (f *CSVFormatter) ContentType() string {
+	return "text/csv; charset=utf-8"
+}
+
+var csvHeader = []string{
+	"section",
+	"id",
+	"winner",
+	"winner_prefix",
+	"winner_tag",
+	"winner_characters",
+	"winner_seed",
+	"score",
+	"outcome",
+	"loser",
+	"loser_prefix",
+	"loser_tag",
+	"loser_characters",
+	"loser_seed",
+	"loser_placement",
+	"winners_bracket",
+	"upset_factor",
+	"completed_at",
+	"vod_url",
+	"stream_name",
+	"stream_source",
+	"grand_final",
+	"bracket_type",
+	"winner_record",
+	"loser_record",
+	"phase",
+	"pool",
+	"winner_placement",
+	"win_probability",
+	"head_to_head_wins",
+	"head_to_head_losses",
+}
+
+// csvText guards text entered on start.gg, such as player names, against
+// being run as a formula when the export is opened in a spreadsheet, by
+// starting cells that begin like one with a quote.
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+func (f *CSVFormatter) Format(upsetThread *domain.UpsetThread, host string) ([]string, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write(csvHeader)
+	for _, section := range sections(upsetThread) {
+		for _, item := range section.items {
+			score := ""
+			if item.Score != nil {
+				score = *item.Score
+			}
+			// Sets that were not rated have no win probability.
+			winProbability := ""
+			if item.WinProbability > 0 {
+				winProbability = strconv.FormatFloat(item.WinProbability, 'f', 4, 64)
+			}
+			// Nor do sets whose head-to-head record is not known.
+			headToHeadWins, headToHeadLosses := "", ""
+			if item.HeadToHead != nil {
+				headToHeadWins = strconv.Itoa(item.HeadToHead.Wins)
+				headToHeadLosses = strconv.Itoa(item.HeadToHead.Losses)
+			}
+			w.Write([]string{
+				section.name,
+				item.Id,
+				csvText(item.WinnersName),
+				csvText(item.WinnersPrefix),
+				csvText(item.WinnersTag),
+				csvText(item.WinnersCharacters),
+				strconv.Itoa(item.WinnersSeed),
+				score,
+				string(item.Outcome),
+				csvText(item.LosersName),
+				csvText(item.LosersPrefix),
+				csvText(item.LosersTag),
+				csvText(item.LosersCharacters),
+				strconv.Itoa(item.LosersSeed),
+				strconv.Itoa(item.LosersPlacement),
+				strconv.FormatBool(item.IsWinnersBracket),
+				strconv.Itoa(item.UpsetFactor),
+				strconv.Itoa(item.CompletedAt),
+				csvText(item.VodUrl),
+				csvText(item.StreamName),
+				item.StreamSource,
+				string(item.GrandFinal),
+				string(item.BracketType),
+				item.WinnersRecord,
+				item.LosersRecord,
+				csvText(item.PhaseName),
+				csvText(item.PoolIdentifier),
+				strconv.Itoa(item.WinnersPlacement),
+				winProbability,
+				headToHeadWins,
+				headToHeadLosses,
+			})
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return []string{buf.String()}, nil
+}
+
+type JSONFormatter struct{}
+
+func (f *JSONFormatter) ContentType() string {
+	return "application/json"
+}
+
+type jsonItem struct {
+	Id                string          `json:"id"`
+	WinnersName       string          `json:"winnersName"`
+	WinnersPrefix     string          `json:"winnersPrefix"`
+	WinnersTag        string          `json:"winnersTag"`
+	WinnersCharacters string          `json:"winnersCharacters"`
+	WinnersSeed       int             `json:"winnersSeed"`
+	Score             *string         `json:"score"`
+	Outcome           string          `json:"outcome"`
+	LosersName        string          `json:"losersName"`
+	LosersPrefix      string          `json:"losersPrefix"`
+	LosersTag         string          `json:"losersTag"`
+	LosersCharacters  string          `json:"losersCharacters"`
+	LosersSeed        int             `json:"losersSeed"`
+	LosersPlacement   int             `json:"losersPlacement"`
+	IsWinnersBracket  bool            `json:"isWinnersBracket"`
+	UpsetFactor       int             `json:"upsetFactor"`
+	CompletedAt       int             `json:"completedAt"`
+	VodUrl            string          `json:"vodUrl"`
+	StreamName        string          `json:"streamName"`
+	StreamSource      string          `json:"streamSource"`
+	GrandFinal        string          `json:"grandFinal"`
+	BracketType       string          `json:"bracketType"`
+	WinnersRecord     string          `json:"winnersRecord"`
+	LosersRecord      string          `json:"losersRecord"`
+	PhaseName         string          `json:"phaseName"`
+	PoolIdentifier    string          `json:"poolIdentifier"`
+	WinnersPlacement  int             `json:"winnersPlacement"`
+	WinProbability    float64         `json:"winProbability"`
+	HeadToHead        *jsonHeadToHead `json:"headToHead"`
+}
+
+// jsonHeadToHead is the winner's record against the loser before the set.
+type jsonHeadToHead struct {
+	Wins     int  `json:"wins"`
+	Losses   int  `json:"losses"`
+	FirstWin bool `json:"firstWin"`
+}
+
+func (f *JSONFormatter) Format(upsetThread *domain.UpsetThread, host string) ([]string, error) {
+	res := map[string]any{
+		"title": upsetThread.Title,
+		"slug":  upsetThread.Slug,
+	}
+	for _, section := range sections(upsetThread) {
+		items := make([]jsonItem, 0, len(section.items))
+		for _, item := range section.items {
+			var headToHead *jsonHeadToHead
+			if item.HeadToHead != nil {
+				headToHead = &jsonHeadToHead{
+					Wins:     item.HeadToHead.Wins,
+					Losses:   item.HeadToHead.Losses,
+					FirstWin: item.HeadToHead.FirstWin(),
+				}
+			}
+			items = append(items, jsonItem{
+				Id:                item.Id,
+				WinnersName:       item.WinnersName,
+				WinnersPrefix:     item.WinnersPrefix,
+				WinnersTag:        item.WinnersTag,
+				WinnersCharacters: item.WinnersCharacters,
+				WinnersSeed:       item.WinnersSeed,
+				Score:             item.Score,
+				Outcome:           string(item.Outcome),
+				LosersName:        item.LosersName,
+				LosersPrefix:      item.LosersPrefix,
+				LosersTag:         item.LosersTag,
+				LosersCharacters:  item.LosersCharacters,
+				LosersSeed:        item.LosersSeed,
+				LosersPlacement:   item.LosersPlacement,
+				IsWinnersBracket:  item.IsWinnersBracket,
+				UpsetFactor:       item.UpsetFactor,
+				CompletedAt:       item.CompletedAt,
+				VodUrl:            item.VodUrl,
+				StreamName:        item.StreamName,
+				StreamSource:      item.StreamSource,
+				GrandFinal:        string(item.GrandFinal),
+				BracketType:       string(item.BracketType),
+				WinnersRecord:     item.WinnersRecord,
+				LosersRecord:      item.LosersRecord,
+				PhaseName:         item.PhaseName,
+				PoolIdentifier:    item.PoolIdentifier,
+				WinnersPlacement:  item.WinnersPlacement,
+				WinProbability:    item.WinProbability,
+				HeadToHead:        headToHead,
+			})
+		}
+		res[section.name] = items
+	}
+	content, err := json.MarshalIndent(res, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return []string{string(content) + "\n"}, nil
+}
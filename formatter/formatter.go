@@ -0,0 +1,67 @@
+package formatter
+
+import (
+	"errors"
+	"fmt"
+	"gg/domain"
+	"sort"
+	"strings"
+)
+
+var ErrorUnknownFormat = errors.New("unknown format")
+
+// FormatterInterface renders an upset thread in one output format. Formats
+// with a length limit return one part per post, comment or message.
+type FormatterInterface interface {
+	ContentType() string
+	Format(upsetThread *domain.UpsetThread, host string) ([]string, error)
+}
+
+// Registry holds the available formatters keyed by format name.
+type Registry struct {
+	formatters map[string]FormatterInterface
+}
+
+func NewRegistry() *Registry {
+	return &Registry{formatters: make(map[string]FormatterInterface)}
+}
+
+func (r *Registry) Register(name string, formatter FormatterInterface) {
+	r.formatters[name] = formatter
+}
+
+func (r *Registry) Get(name string) (FormatterInterface, error) {
+	formatter, ok := r.formatters[name]
+	if !ok {
+		return nil, fmt.Errorf("%w %q, expected one of %s", ErrorUnknownFormat, name, strings.Join(r.Names(), ", "))
+	}
+	return formatter, nil
+}
+
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.formatters))
+	for name := range r.formatters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+type section struct {
+	name  string
+	items []domain.UpsetThreadItem
+}
+
+// sections lists every section of the thread in display order, including
+// sets that did not make it into any of the others.
+func sections(upsetThread *domain.UpsetThread) []section {
+	return []section{
+		{"grandfinals", upsetThread.GrandFinals},
+		{"winners", upsetThread.Winners},
+		{"losers", upsetThread.Losers},
+		{"pools", upsetThread.Pools},
+		{"notables", upsetThread.Notables},
+		{"dqs", upsetThread.DQs},
+		{"other", upsetThread.Other},
+	}
+}
@@ -0,0 +1,101 @@
+package formatter
+
+import (
+	"encoding/json"
+	"errors"
+	"gg/domain"
+	"strings"
+	"testing"
+)
+
+var score = "3-2"
+
+var upsetThread = &domain.UpsetThread{
+	Title: "Title",
+	Slug:  "tournament/sample/event/sample",
+	Winners: []domain.UpsetThreadItem{{
+		Id:                "1",
+		WinnersName:       "Mar",
+		WinnersCharacters: "Bayonetta",
+		WinnersSeed:       62,
+		Score:             &score,
+		Outcome:           domain.OutcomeScore,
+		LosersName:        "LG | Zomba",
+		LosersPrefix:      "LG",
+		LosersTag:         "Zomba",
+		LosersCharacters:  "R.O.B., Wolf",
+		IsWinnersBracket:  true,
+		LosersSeed:        3,
+		UpsetFactor:       9,
+		VodUrl:            "https://youtu.be/vod",
+		PhaseName:         "Top 64",
+		WinProbability:    0.125,
+		HeadToHead:        &domain.HeadToHead{Losses: 2},
+	}},
+	Other: []domain.UpsetThreadItem{{Id: "2", WinnersName: "Sonix", LosersName: "Tweek"}},
+}
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register("json", &JSONFormatter{})
+	registry.Register("csv", &CSVFormatter{})
+	if _, err := registry.Get("csv"); err != nil {
+		t.Errorf("Expected csv format, got %s", err)
+	}
+	_, err := registry.Get("xml")
+	if !errors.Is(err, ErrorUnknownFormat) || !strings.Contains(err.Error(), "csv, json") {
+		t.Errorf("Expected unknown format error listing formats, got %v", err)
+	}
+}
+
+func TestCSVFormatter(t *testing.T) {
+	res, err := (&CSVFormatter{}).Format(upsetThread, "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	expected := "section,id,winner,winner_prefix,winner_tag,winner_characters,winner_seed,score,outcome,loser,loser_prefix,loser_tag,loser_characters,loser_seed,loser_placement,winners_bracket,upset_factor,completed_at,vod_url,stream_name,stream_source,grand_final,bracket_type,winner_record,loser_record,phase,pool,winner_placement,win_probability,head_to_head_wins,head_to_head_losses\n" +
+		"winners,1,Mar,,,Bayonetta,62,3-2,score,LG | Zomba,LG,Zomba,\"R.O.B., Wolf\",3,0,true,9,0,https://youtu.be/vod,,,,,,,Top 64,,0,0.1250,0,2\n" +
+		"other,2,Sonix,,,,0,,,Tweek,,,,0,0,false,0,0,,,,,,,,,,0,,,\n"
+	if res[0] != expected {
+		t.Errorf("Expected %s, got %s", expected, res[0])
+	}
+}
+
+func TestCSVFormatterFormulas(t *testing.T) {
+	upsetThread := &domain.UpsetThread{Winners: []domain.UpsetThreadItem{{
+		Id:               "1",
+		WinnersName:      `=HYPERLINK("https://example.com","Mar")`,
+		WinnersTag:       "+Mar",
+		LosersName:       "@Zomba",
+		LosersCharacters: "-Wolf",
+	}}}
+	res, err := (&CSVFormatter{}).Format(upsetThread, "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	expected := `winners,1,"'=HYPERLINK(""https://example.com"",""Mar"")",,'+Mar,,0,,,'@Zomba,,,'-Wolf,`
+	if !strings.Contains(res[0], expected) {
+		t.Errorf("Expected cells starting like formulas to be quoted, got %s", res[0])
+	}
+}
+
+func TestJSONFormatter(t *testing.T) {
+	res, err := (&JSONFormatter{}).Format(upsetThread, "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	var decoded struct {
+		Title   string     `json:"title"`
+		Winners []jsonItem `json:"winners"`
+		Losers  []jsonItem `json:"losers"`
+	}
+	if err := json.Unmarshal([]byte(res[0]), &decoded); err != nil {
+		t.Fatalf("Expected valid JSON, got %s", err)
+	}
+	if decoded.Title != "Title" || len(decoded.Winners) != 1 || *decoded.Winners[0].Score != "3-2" || decoded.Winners[0].Outcome != "score" || decoded.Winners[0].PhaseName != "Top 64" || decoded.Losers == nil {
+		t.Errorf("Unexpected JSON %s", res[0])
+	}
+	if h := decoded.Winners[0].HeadToHead; h == nil || h.Losses != 2 || !h.FirstWin {
+		t.Errorf("Expected the head-to-head record, got %+v", h)
+	}
+}
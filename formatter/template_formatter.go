@@ -0,0 +1,47 @@
+package formatter
+
+import (
+	"gg/domain"
+	"gg/mapper"
+)
+
+// TemplateFormatter renders the thread's display through a template, split
+// into parts when a splitter is given.
+type TemplateFormatter struct {
+	contentType string
+	options     *mapper.DisplayOptions
+	render      mapper.RenderFunc
+	splitter    *mapper.ThreadSplitter
+}
+
+func NewTemplateFormatter(contentType string, options *mapper.DisplayOptions, render mapper.RenderFunc) *TemplateFormatter {
+	return &TemplateFormatter{
+		contentType: contentType,
+		options:     options,
+		render:      render,
+	}
+}
+
+func NewSplitTemplateFormatter(contentType string, options *mapper.DisplayOptions, splitter *mapper.ThreadSplitter) *TemplateFormatter {
+	return &TemplateFormatter{
+		contentType: contentType,
+		options:     options,
+		splitter:    splitter,
+	}
+}
+
+func (f *TemplateFormatter) ContentType() string {
+	return f.contentType
+}
+
+func (f *TemplateFormatter) Format(upsetThread *domain.UpsetThread, host string) ([]string, error) {
+	display := mapper.ToDisplayWithOptions(upsetThread, host, f.options)
+	if f.splitter != nil {
+		return f.splitter.Split(display)
+	}
+	content, err := f.render(display)
+	if err != nil {
+		return nil, err
+	}
+	return []string{content}, nil
+}
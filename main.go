@@ -3,93 +3,205 @@ package main
 import (
 	"bytes"
 	"context"
-	"flag"
+	"errors"
 	"fmt"
 	"gg/client/graphql"
 	"gg/client/startgg"
+	"gg/config"
 	"gg/db"
 	"gg/domain"
 	"gg/mapper"
 	"gg/service"
+	"io"
 	"log"
 	"net/http"
 	"os"
-	"text/template"
+	"strings"
 	"time"
+	_ "time/tzdata"
 
 	"github.com/gorilla/websocket"
 	"github.com/redis/go-redis/v9"
 )
 
-const (
-	// Time allowed to write the file to the client.
-	writeWait = 10 * time.Second
-
-	// Time allowed to read the next pong message from the client.
-	pongWait = 60 * time.Second
-
-	// Send pings to client with this period. Must be less than pongWait.
-	pingPeriod = (pongWait * 9) / 10
-)
-
-var (
-	addr                    = flag.String("addr", ":8080", "http service address")
-	slug                    = flag.String("slug", "", "Slug.")
-	title                   = flag.String("title", "", "Title.")
-	subreddit               = flag.String("subreddit", "", "Subreddit.")
-	file                    = flag.String("file", "", "File.")
-	upsetThreadTemplate     = template.Must(template.ParseFiles("template/upset-thread.tmpl"))
-	upsetThreadHTMLTemplate = template.Must(template.ParseFiles("template/upset-thread.html"))
-	upgrader                = websocket.Upgrader{
-		ReadBufferSize:  1024,
-		WriteBufferSize: 1024,
-	}
-)
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+}
 
 type IndexHandler struct {
-	service service.ServiceInterface
+	tracker   service.TrackerInterface
+	templates *templates
+	slug      string
+}
+
+type EventHandler struct {
+	tracker   service.TrackerInterface
+	templates *templates
 }
 
 type WebSockerHandler struct {
-	service         service.ServiceInterface
-	upsetThreadChan chan *domain.UpsetThreadDisplay
+	tracker   service.TrackerInterface
+	templates *templates
+	// Time allowed to write the file to the client.
+	writeWait time.Duration
+	// Time allowed to read the next pong message from the client.
+	pongWait time.Duration
+	// Send pings to client with this period. Must be less than pongWait.
+	pingPeriod time.Duration
 }
 
 func main() {
-	flag.Parse()
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "config":
+			runConfigCommand(os.Args[2:])
+			return
+		case "format":
+			runFormatCommand(os.Args[2:])
+			return
+		case "card":
+			runCardCommand(os.Args[2:])
+			return
+		case "season":
+			runSeasonCommand(os.Args[2:])
+			return
+		case "seeding":
+			runSeedingCommand(os.Args[2:])
+			return
+		case "simulate":
+			runSimulateCommand(os.Args[2:])
+			return
+		}
+	}
+	cfg, err := config.Load("gg", os.Args[1:], os.Getenv)
+	if err != nil {
+		log.Fatalf("Error while loading config. e=%s\n", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid config.\n%s\n", err)
+	}
+	templates, err := loadTemplates(templateFS(cfg.TemplateDir), cfg)
+	if err != nil {
+		log.Fatalf("Invalid templates.\n%s\n", err)
+	}
 
-	var service service.ServiceInterface = service.NewService(
-		db.NewRedisDBService(*redis.NewClient(&redis.Options{Addr: os.Getenv("REDIS_URL")}), context.Background()),
-		startgg.NewClient(graphql.NewClient(os.Getenv("START_GG_API_URL"), os.Getenv("START_GG_API_KEY"), &http.Client{})),
+	dbService := db.NewRedisDBService(*redis.NewClient(&redis.Options{Addr: cfg.Redis.URL}), context.Background())
+	var upsetThreadService service.ServiceInterface = service.NewService(
+		dbService,
+		startgg.NewClient(
+			graphql.NewClient(cfg.StartGG.APIURL, cfg.StartGG.APIKey, &http.Client{}),
+			cfg.StartGG.MaxRetries,
+			time.Duration(cfg.StartGG.BaseDelay),
+		),
 		&service.FileReaderWriter{},
+		time.Duration(cfg.StartGG.PageDelay),
+		cfg.NotableRules(),
 	)
-	indexHandler := IndexHandler{
-		service: service,
+	var tracker service.TrackerInterface = service.NewTracker(upsetThreadService, dbService, templates.writeMdFile, postWebhook, time.Duration(cfg.PollInterval))
+	tracker.Start()
+	for _, event := range append([]config.EventConfig{cfg.Event}, cfg.Events...) {
+		if event.Slug == "" {
+			continue
+		}
+		err := tracker.AddEvent(domain.Event{
+			Slug:       event.Slug,
+			Title:      event.Title,
+			Subreddit:  event.Subreddit,
+			File:       event.File,
+			Timezone:   event.Timezone,
+			TimeFormat: event.TimeFormat,
+		})
+		if err == service.ErrorEventAlreadyExists {
+			err = errors.Join(
+				tracker.UpdateTitle(event.Slug, event.Title),
+				tracker.UpdateDisplay(event.Slug, event.Timezone, event.TimeFormat),
+			)
+		}
+		if err != nil {
+			log.Fatalf("Error while adding event. e=%s\n", err)
+		}
 	}
 
-	webSocketHandler := WebSockerHandler{
-		service:         service,
-		upsetThreadChan: make(chan *domain.UpsetThreadDisplay),
-	}
+	fs := http.FileServerFS(staticFS(cfg.TemplateDir))
+	http.Handle("/static/", http.StripPrefix("/static/", fs))
+	http.Handle("/", &IndexHandler{tracker: tracker, templates: templates, slug: cfg.Event.Slug})
+	http.Handle("GET /event/tournament/{tournament}/event/{event}", &EventHandler{tracker: tracker, templates: templates})
+	handleFeeds(tracker)
+	handleOverlay(tracker, upsetThreadService, templates)
+	handleWatchlists(tracker, templates)
+	handleSeasons(tracker, templates)
+	handlePlayers(tracker, templates)
+	handleCharacters(tracker, templates)
+	handleSeeding(tracker, templates)
+	handleSimulations(tracker, templates)
+	http.Handle("GET /event/tournament/{tournament}/event/{event}/cards/{card}", &CardHandler{tracker: tracker})
+	http.Handle("/ws", &WebSockerHandler{
+		tracker:    tracker,
+		templates:  templates,
+		writeWait:  time.Duration(cfg.WebSocket.WriteWait),
+		pongWait:   time.Duration(cfg.WebSocket.PongWait),
+		pingPeriod: cfg.WebSocket.PingPeriod(),
+	})
+	handleAdmin(tracker, templates, cfg.Admin)
+	http.ListenAndServe(cfg.Addr, nil)
+}
 
-	go webSocketHandler.getUpsetThreadDisplay()
+// writeMdFile writes the thread to output/, with one extra file per comment
+// when it is too long for a single Reddit post.
+func (t *templates) writeMdFile(upsetThread *domain.UpsetThread) error {
+	markdown, err := t.formats.Get("md-reddit")
+	if err != nil {
+		return err
+	}
+	parts, err := markdown.Format(upsetThread, "")
+	if err != nil {
+		return fmt.Errorf("error while formatting thread: %w", err)
+	}
+	prefix := fmt.Sprintf("output/%v %s", time.Now().UnixMilli(), upsetThread.Title)
+	for i, part := range parts {
+		filename := prefix + ".md"
+		if i > 0 {
+			filename = fmt.Sprintf("%s (comment %d).md", prefix, i)
+		}
+		if err := os.WriteFile(filename, []byte(part), 0o644); err != nil {
+			return fmt.Errorf("error while creating file: %w", err)
+		}
+	}
+	return nil
+}
 
-	fs := http.FileServer(http.Dir("./static"))
-	http.Handle("/static/", http.StripPrefix("/static/", fs))
-	http.Handle("/", &indexHandler)
-	http.Handle("/ws", &webSocketHandler)
-	http.ListenAndServe(*addr, nil)
+// eventSlug rebuilds the start.gg event slug from an event route's path values.
+func eventSlug(r *http.Request) string {
+	return "tournament/" + r.PathValue("tournament") + "/event/" + r.PathValue("event")
 }
 
-func writeMdFile(upsetThreadDisplay *domain.UpsetThreadDisplay) {
-	mdTemplate := template.Must(template.ParseFiles("template/markdown.tmpl"))
-	filename := fmt.Sprintf("output/%v %s.md", time.Now().UnixMilli(), *title)
-	outputFile, err := os.Create(filename)
+// renderEvent writes the event in the format given by ?format=, defaulting
+// to the live HTML page.
+func renderEvent(w http.ResponseWriter, r *http.Request, tracker service.TrackerInterface, templates *templates, slug string) *domain.UpsetThread {
+	name := r.URL.Query().Get("format")
+	if name == "" {
+		name = "html"
+	}
+	format, err := templates.formats.Get(name)
 	if err != nil {
-		log.Fatalf("Error while creating file. e=%s\n", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return nil
+	}
+	upsetThread, err := tracker.GetUpsetThread(slug)
+	if err != nil {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return nil
 	}
-	defer outputFile.Close()
-	mdTemplate.Execute(outputFile, &upsetThreadDisplay)
+	parts, err := format.Format(upsetThread, r.Host)
+	if err != nil {
+		log.Printf("Error while formatting event. slug=%s format=%s e=%s\n", slug, name, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return nil
+	}
+	w.Header().Set("Content-Type", format.ContentType())
+	io.WriteString(w, strings.Join(parts, "\n"))
+	return upsetThread
 }
 
 func (h *IndexHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -101,18 +213,29 @@ func (h *IndexHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
-	upsetThread := h.service.GetUpsetThreadDB(*slug, *title)
-	upsetThreadDisplay := mapper.ToDisplay(upsetThread, r.Host)
-	w.Header().Set("Content-Type", "text/html; charset=utf-8")
-	upsetThreadHTMLTemplate.Execute(w, &upsetThreadDisplay)
-	writeMdFile(upsetThreadDisplay)
+	if h.slug == "" {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		h.templates.indexHTML.Execute(w, h.tracker.GetEvents())
+		return
+	}
+	upsetThread := renderEvent(w, r, h.tracker, h.templates, h.slug)
+	if upsetThread == nil {
+		return
+	}
+	if err := h.templates.writeMdFile(upsetThread); err != nil {
+		log.Println(err)
+	}
+}
+
+func (h *EventHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	renderEvent(w, r, h.tracker, h.templates, eventSlug(r))
 }
 
-func reader(ws *websocket.Conn) {
+func (h *WebSockerHandler) reader(ws *websocket.Conn) {
 	defer ws.Close()
 	ws.SetReadLimit(512)
-	ws.SetReadDeadline(time.Now().Add(pongWait))
-	ws.SetPongHandler(func(string) error { ws.SetReadDeadline(time.Now().Add(pongWait)); return nil })
+	ws.SetReadDeadline(time.Now().Add(h.pongWait))
+	ws.SetPongHandler(func(string) error { ws.SetReadDeadline(time.Now().Add(h.pongWait)); return nil })
 	for {
 		_, _, err := ws.ReadMessage()
 		if err != nil {
@@ -121,31 +244,22 @@ func reader(ws *websocket.Conn) {
 	}
 }
 
-func (h *WebSockerHandler) getUpsetThreadDisplay() {
-	for {
-		upsetThread := h.service.Process(*slug, *title, *subreddit, *file, "")
-		upsetThreadDisplay := mapper.ToDisplay(upsetThread, "")
-		h.upsetThreadChan <- upsetThreadDisplay
-	}
-}
-
-func (h *WebSockerHandler) writer(ws *websocket.Conn) {
+// writer sends every update of the event, or of every event for AllEvents,
+// as rendered by render.
+func (h *WebSockerHandler) writer(ws *websocket.Conn, slug string, render renderUpdate) {
 	lastError := ""
-	pingTicker := time.NewTicker(pingPeriod)
+	pingTicker := time.NewTicker(h.pingPeriod)
+	upsetThreadChan := h.tracker.Subscribe(slug)
 
 	defer func() {
+		h.tracker.Unsubscribe(slug, upsetThreadChan)
 		pingTicker.Stop()
 		ws.Close()
 	}()
 	for {
 		select {
-		case upsetThreadDisplay := <-h.upsetThreadChan:
-			var p []byte
-			var err error
-
-			var buff bytes.Buffer
-			upsetThreadTemplate.Execute(&buff, upsetThreadDisplay)
-			p = buff.Bytes()
+		case upsetThread := <-upsetThreadChan:
+			p, err := render(upsetThread)
 
 			if err != nil {
 				if s := err.Error(); s != lastError {
@@ -157,20 +271,71 @@ func (h *WebSockerHandler) writer(ws *websocket.Conn) {
 			}
 
 			if p != nil {
-				ws.SetWriteDeadline(time.Now().Add(writeWait))
+				ws.SetWriteDeadline(time.Now().Add(h.writeWait))
 				if err := ws.WriteMessage(websocket.TextMessage, p); err != nil {
 					return
 				}
 			}
 		case <-pingTicker.C:
-			ws.SetWriteDeadline(time.Now().Add(writeWait))
+			ws.SetWriteDeadline(time.Now().Add(h.writeWait))
 			if err := ws.WriteMessage(websocket.PingMessage, []byte{}); err != nil {
 				return
 			}
 		}
 	}
 }
+
+// renderUpdate renders an update of an event for a websocket client.
+type renderUpdate func(upsetThread *domain.UpsetThread) ([]byte, error)
+
+// renderer returns the event to subscribe to and how its updates are sent:
+// as an HTML fragment of the event's live page, in the format given by
+// ?format=, or as the fragment of the watchlist page given by ?watchlist=.
+func (h *WebSockerHandler) renderer(w http.ResponseWriter, r *http.Request) (string, renderUpdate, bool) {
+	if id := r.URL.Query().Get("watchlist"); id != "" {
+		if _, err := h.tracker.GetWatchlist(id); err != nil {
+			http.Error(w, "Not found", http.StatusNotFound)
+			return "", nil, false
+		}
+		return service.AllEvents, func(upsetThread *domain.UpsetThread) ([]byte, error) {
+			display, err := watchlistDisplay(h.tracker, h.templates, id, "")
+			if err != nil {
+				return nil, err
+			}
+			var buff bytes.Buffer
+			err = h.templates.watchlistHTML.ExecuteTemplate(&buff, "watchlist", display)
+			return buff.Bytes(), err
+		}, true
+	}
+	slug := r.URL.Query().Get("slug")
+	if _, err := h.tracker.GetEvent(slug); err != nil {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return "", nil, false
+	}
+	if name := r.URL.Query().Get("format"); name != "" {
+		format, err := h.templates.formats.Get(name)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return "", nil, false
+		}
+		return slug, func(upsetThread *domain.UpsetThread) ([]byte, error) {
+			parts, err := format.Format(upsetThread, "")
+			return []byte(strings.Join(parts, "\n")), err
+		}, true
+	}
+	return slug, func(upsetThread *domain.UpsetThread) ([]byte, error) {
+		upsetThreadDisplay := mapper.ToDisplayWithOptions(upsetThread, "", h.templates.displayOptions["html"])
+		var buff bytes.Buffer
+		err := h.templates.upsetThread.Execute(&buff, upsetThreadDisplay)
+		return buff.Bytes(), err
+	}, true
+}
+
 func (h *WebSockerHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	slug, render, ok := h.renderer(w, r)
+	if !ok {
+		return
+	}
 	ws, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		if _, ok := err.(websocket.HandshakeError); !ok {
@@ -179,6 +344,6 @@ func (h *WebSockerHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	go h.writer(ws)
-	reader(ws)
+	go h.writer(ws, slug, render)
+	h.reader(ws)
 }
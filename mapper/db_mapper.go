@@ -13,11 +13,13 @@ func DBSetToUpsetThreadItem(setId, set string) *domain.UpsetThreadItem {
 		log.Fatalf("Error while unmarshaling to upset thread item. e=%s\n", err)
 	}
 	score := arr[3].(string)
-	return &domain.UpsetThreadItem{
+	item := &domain.UpsetThreadItem{
+		Id:                setId,
 		WinnersName:       arr[0].(string),
 		WinnersCharacters: arr[1].(string),
 		WinnersSeed:       int(arr[2].(float64)),
 		Score:             &score,
+		Outcome:           domain.ScoreOutcome(score),
 		LosersName:        arr[4].(string),
 		LosersCharacters:  arr[5].(string),
 		IsWinnersBracket:  arr[6].(bool),
@@ -26,10 +28,99 @@ func DBSetToUpsetThreadItem(setId, set string) *domain.UpsetThreadItem {
 		UpsetFactor:       int(arr[9].(float64)),
 		CompletedAt:       int(arr[10].(float64)),
 		Category:          arr[11].(string),
+		VodUrl:            optionalString(arr, 12),
+		StreamName:        optionalString(arr, 13),
+		StreamSource:      optionalString(arr, 14),
+		WinnersPlayerIds:  optionalInts(arr, 15),
+		LosersPlayerIds:   optionalInts(arr, 16),
+		WinnersPrefix:     optionalString(arr, 17),
+		WinnersTag:        optionalString(arr, 18),
+		LosersPrefix:      optionalString(arr, 19),
+		LosersTag:         optionalString(arr, 20),
+		GrandFinal:        domain.GrandFinal(optionalString(arr, 21)),
+		BracketType:       domain.BracketType(optionalString(arr, 22)),
+		WinnersRecord:     optionalString(arr, 23),
+		LosersRecord:      optionalString(arr, 24),
+		PhaseName:         optionalString(arr, 25),
+		PoolIdentifier:    optionalString(arr, 26),
+		WinnersPlacement:  optionalInt(arr, 27),
+		WinProbability:    optionalFloat(arr, 28),
+		HeadToHead:        optionalHeadToHead(arr, 29),
 	}
+	// Sets stored before the flag are notable when listed as such.
+	item.Notable = optionalBool(arr, 30, item.Category == "notables")
+	if item.WinnersTag == "" {
+		item.WinnersPrefix, item.WinnersTag = domain.SplitName(item.WinnersName)
+	}
+	if item.LosersTag == "" {
+		item.LosersPrefix, item.LosersTag = domain.SplitName(item.LosersName)
+	}
+	return item
+}
+
+// optionalString reads a trailing element that sets stored by older
+// versions do not have.
+func optionalString(arr []interface{}, i int) string {
+	if len(arr) <= i {
+		return ""
+	}
+	s, _ := arr[i].(string)
+	return s
+}
+
+func optionalInt(arr []interface{}, i int) int {
+	if len(arr) <= i {
+		return 0
+	}
+	f, _ := arr[i].(float64)
+	return int(f)
+}
+
+func optionalBool(arr []interface{}, i int, fallback bool) bool {
+	if len(arr) <= i {
+		return fallback
+	}
+	b, _ := arr[i].(bool)
+	return b
+}
+
+func optionalFloat(arr []interface{}, i int) float64 {
+	if len(arr) <= i {
+		return 0
+	}
+	f, _ := arr[i].(float64)
+	return f
+}
+
+// optionalHeadToHead reads a head-to-head record stored as its wins and
+// losses.
+func optionalHeadToHead(arr []interface{}, i int) *domain.HeadToHead {
+	record := optionalInts(arr, i)
+	if len(record) != 2 {
+		return nil
+	}
+	return &domain.HeadToHead{Wins: record[0], Losses: record[1]}
+}
+
+func optionalInts(arr []interface{}, i int) []int {
+	if len(arr) <= i {
+		return nil
+	}
+	values, _ := arr[i].([]interface{})
+	var res []int
+	for _, value := range values {
+		if f, ok := value.(float64); ok {
+			res = append(res, int(f))
+		}
+	}
+	return res
 }
 
 func UpsetThreadItemToDBSet(item domain.UpsetThreadItem) string {
+	var headToHead []int
+	if item.HeadToHead != nil {
+		headToHead = []int{item.HeadToHead.Wins, item.HeadToHead.Losses}
+	}
 	res, err := json.Marshal([]interface{}{
 		item.WinnersName,
 		item.WinnersCharacters,
@@ -43,9 +134,108 @@ func UpsetThreadItemToDBSet(item domain.UpsetThreadItem) string {
 		item.UpsetFactor,
 		item.CompletedAt,
 		item.Category,
+		item.VodUrl,
+		item.StreamName,
+		item.StreamSource,
+		item.WinnersPlayerIds,
+		item.LosersPlayerIds,
+		item.WinnersPrefix,
+		item.WinnersTag,
+		item.LosersPrefix,
+		item.LosersTag,
+		item.GrandFinal,
+		item.BracketType,
+		item.WinnersRecord,
+		item.LosersRecord,
+		item.PhaseName,
+		item.PoolIdentifier,
+		item.WinnersPlacement,
+		item.WinProbability,
+		headToHead,
+		item.Notable,
 	})
 	if err != nil {
 		log.Fatalf("Error while marshaling to db set. e=%s\n", err)
 	}
 	return string(res)
 }
+
+func DBEventToEvent(event string) *domain.Event {
+	var res domain.Event
+	if err := json.Unmarshal([]byte(event), &res); err != nil {
+		log.Fatalf("Error while unmarshaling to event. e=%s\n", err)
+	}
+	return &res
+}
+
+func EventToDBEvent(event domain.Event) string {
+	res, err := json.Marshal(event)
+	if err != nil {
+		log.Fatalf("Error while marshaling to db event. e=%s\n", err)
+	}
+	return string(res)
+}
+
+func DBWatchlistToWatchlist(watchlist string) *domain.Watchlist {
+	var res domain.Watchlist
+	if err := json.Unmarshal([]byte(watchlist), &res); err != nil {
+		log.Fatalf("Error while unmarshaling to watchlist. e=%s\n", err)
+	}
+	return &res
+}
+
+func WatchlistToDBWatchlist(watchlist domain.Watchlist) string {
+	res, err := json.Marshal(watchlist)
+	if err != nil {
+		log.Fatalf("Error while marshaling to db watchlist. e=%s\n", err)
+	}
+	return string(res)
+}
+
+func DBSeasonToSeason(season string) *domain.Season {
+	var res domain.Season
+	if err := json.Unmarshal([]byte(season), &res); err != nil {
+		log.Fatalf("Error while unmarshaling to season. e=%s\n", err)
+	}
+	return &res
+}
+
+func SeasonToDBSeason(season domain.Season) string {
+	res, err := json.Marshal(season)
+	if err != nil {
+		log.Fatalf("Error while marshaling to db season. e=%s\n", err)
+	}
+	return string(res)
+}
+
+func DBPlayerRatingToPlayerRating(rating string) *domain.PlayerRating {
+	var res domain.PlayerRating
+	if err := json.Unmarshal([]byte(rating), &res); err != nil {
+		log.Fatalf("Error while unmarshaling to player rating. e=%s\n", err)
+	}
+	return &res
+}
+
+func PlayerRatingToDBPlayerRating(rating domain.PlayerRating) string {
+	res, err := json.Marshal(rating)
+	if err != nil {
+		log.Fatalf("Error while marshaling to db player rating. e=%s\n", err)
+	}
+	return string(res)
+}
+
+func DBHeadToHeadToHeadToHead(history string) []domain.HeadToHeadSet {
+	var res []domain.HeadToHeadSet
+	if err := json.Unmarshal([]byte(history), &res); err != nil {
+		log.Fatalf("Error while unmarshaling to head-to-head. e=%s\n", err)
+	}
+	return res
+}
+
+func HeadToHeadToDBHeadToHead(history []domain.HeadToHeadSet) string {
+	res, err := json.Marshal(history)
+	if err != nil {
+		log.Fatalf("Error while marshaling to db head-to-head. e=%s\n", err)
+	}
+	return string(res)
+}
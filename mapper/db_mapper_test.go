@@ -0,0 +1,56 @@
+package mapper
+
+import (
+	"gg/domain"
+	"reflect"
+	"testing"
+)
+
+func TestDBSetRoundTrip(t *testing.T) {
+	score := "3-2"
+	item := domain.UpsetThreadItem{
+		Id:               "63321153",
+		WinnersName:      "Mar",
+		WinnersTag:       "Mar",
+		WinnersSeed:      62,
+		Score:            &score,
+		Outcome:          domain.OutcomeScore,
+		LosersName:       "LG | Zomba",
+		LosersPrefix:     "LG",
+		LosersTag:        "Zomba",
+		LosersSeed:       3,
+		UpsetFactor:      9,
+		Category:         "winners",
+		VodUrl:           "https://youtu.be/vod",
+		StreamName:       "btssmash",
+		StreamSource:     "TWITCH",
+		WinnersPlayerIds: []int{1004},
+		LosersPlayerIds:  []int{2005},
+		PhaseName:        "Pools",
+		PoolIdentifier:   "A12",
+		WinnersPlacement: 7,
+		WinProbability:   0.25,
+		HeadToHead:       &domain.HeadToHead{Wins: 2, Losses: 1},
+		Notable:          true,
+	}
+	res := DBSetToUpsetThreadItem(item.Id, UpsetThreadItemToDBSet(item))
+	if !reflect.DeepEqual(*res, item) {
+		t.Errorf("Expected %+v, got %+v", item, *res)
+	}
+}
+
+func TestDBSetFromOlderVersions(t *testing.T) {
+	res := DBSetToUpsetThreadItem("1", `["Mar","",62,"3-2","ST|BLZ | MrLasagna","",true,3,0,9,0,"winners"]`)
+	if res.Id != "1" || res.VodUrl != "" || res.StreamName != "" {
+		t.Errorf("Expected a set stored without links to load, got %+v", *res)
+	}
+	if res.WinnersTag != "Mar" || res.LosersPrefix != "ST|BLZ" || res.LosersTag != "MrLasagna" {
+		t.Errorf("Expected names to be split, got %+v", *res)
+	}
+	if res.Notable {
+		t.Errorf("Expected an upset stored without the flag not to be notable")
+	}
+	if res := DBSetToUpsetThreadItem("2", `["Mar","",3,"3-2","Zomba","",true,62,0,-9,0,"notables"]`); !res.Notable {
+		t.Errorf("Expected a notable stored without the flag to be notable")
+	}
+}
@@ -1,78 +1,195 @@
 package mapper
 
 import (
+	"cmp"
+	"fmt"
 	"gg/domain"
 	"log"
-	"strconv"
-	"strings"
+	"math"
+	"net/url"
+	"slices"
 	"time"
 )
 
 func toLineItemDisplay(item domain.UpsetThreadItem) *domain.UpsetThreadItemDisplay {
-	words := []string{item.WinnersName}
-	if len(item.WinnersCharacters) > 0 {
-		words = append(words, "("+item.WinnersCharacters+")")
+	return DefaultLineFormat.toLineItemDisplay(item)
+}
+
+func toDQLineItemDisplay(item domain.UpsetThreadItem) *domain.UpsetThreadItemDisplay {
+	return &domain.UpsetThreadItemDisplay{
+		Content: item.LosersName,
 	}
-	words = append(words, "(seed "+strconv.Itoa(item.WinnersSeed)+")")
-	words = append(words, *item.Score)
-	words = append(words, item.LosersName)
-	if len(item.LosersCharacters) > 0 {
-		words = append(words, "("+item.LosersCharacters+")")
+}
+
+// SetUrl links to the set's page on start.gg.
+func SetUrl(slug, setId string) string {
+	return "https://www.start.gg/" + slug + "/set/" + setId
+}
+
+func withLinks(display *domain.UpsetThreadItemDisplay, slug string, item domain.UpsetThreadItem) *domain.UpsetThreadItemDisplay {
+	if item.Id != "" {
+		display.Url = SetUrl(slug, item.Id)
+	}
+	// VOD links are entered by tournament organisers, so only absolute web
+	// links are kept.
+	if u, err := url.Parse(item.VodUrl); err == nil && (u.Scheme == "https" || u.Scheme == "http") && u.Host != "" {
+		display.VodUrl = u.String()
+	}
+	return display
+}
+
+const (
+	DefaultTimezone   = "America/Los_Angeles"
+	DefaultTimeFormat = "01/02/2006 03:04pm MST"
+)
+
+type DisplayOptions struct {
+	LineFormat *LineFormat
+	Now        func() time.Time
+	// TagOnly leaves the sponsor prefixes out of player names.
+	TagOnly bool
+	// PhaseLabels starts each line with the phase and pool of the set, such
+	// as "[Pools A12]".
+	PhaseLabels bool
+	// Phases keeps the sets of the named phases only, ignoring case. Every
+	// phase is kept when empty.
+	Phases []string
+	// RankBySurprise orders the upsets by the winners' pre-match win
+	// probability, least likely first, instead of by upset factor.
+	RankBySurprise bool
+}
+
+func DefaultDisplayOptions() *DisplayOptions {
+	return &DisplayOptions{
+		LineFormat: DefaultLineFormat,
+		Now:        time.Now,
 	}
-	losersSeed := "(seed " + strconv.Itoa(item.LosersSeed) + ")"
-	if item.IsWinnersBracket {
-		words = append(words, losersSeed)
-	} else {
-		words = append(words, losersSeed+", out at "+getOrdinal(item.LosersPlacement))
+}
+
+// getLocation loads the timezone, falling back to DefaultTimezone and then
+// UTC when it is empty or unknown.
+func getLocation(timezone string) *time.Location {
+	for _, name := range []string{timezone, DefaultTimezone} {
+		if name == "" {
+			continue
+		}
+		location, err := time.LoadLocation(name)
+		if err == nil {
+			return location
+		}
+		log.Printf("Error while loading location. name=%s e=%s\n", name, err)
 	}
-	if item.UpsetFactor > 0 {
-		words = append(words, "- Upset Factor "+strconv.Itoa(item.UpsetFactor))
+	return time.UTC
+}
+
+func ToDisplay(upsetThread *domain.UpsetThread, host string) *domain.UpsetThreadDisplay {
+	return ToDisplayWithOptions(upsetThread, host, DefaultDisplayOptions())
+}
+
+// withNames replaces the entrant names with their gamer tags when the output
+// leaves out sponsor prefixes.
+func withNames(item domain.UpsetThreadItem, options *DisplayOptions) domain.UpsetThreadItem {
+	if !options.TagOnly {
+		return item
 	}
-	content := strings.Join(words, " ")
-	var bold bool
-	if item.UpsetFactor >= 4 {
-		bold = true
+	if item.WinnersTag != "" {
+		item.WinnersName = item.WinnersTag
 	}
-	return &domain.UpsetThreadItemDisplay{
-		Content: content,
-		Bold:    bold,
+	if item.LosersTag != "" {
+		item.LosersName = item.LosersTag
 	}
+	return item
 }
 
-func toDQLineItemDisplay(item domain.UpsetThreadItem) *domain.UpsetThreadItemDisplay {
-	return &domain.UpsetThreadItemDisplay{
-		Content: item.LosersName,
+// toItemDisplays renders the items of a section that are in the output's
+// phases.
+func toItemDisplays(items []domain.UpsetThreadItem, slug string, options *DisplayOptions, toLine func(domain.UpsetThreadItem) *domain.UpsetThreadItemDisplay) []*domain.UpsetThreadItemDisplay {
+	var res []*domain.UpsetThreadItemDisplay
+	for _, s := range items {
+		if !domain.InPhases(s.PhaseName, options.Phases) {
+			continue
+		}
+		display := withLinks(toLine(withNames(s, options)), slug, s)
+		if label := s.PhaseLabel(); options.PhaseLabels && label != "" {
+			display.Content = "[" + label + "] " + display.Content
+		}
+		res = append(res, display)
 	}
+	return res
 }
 
-func ToDisplay(upsetThread *domain.UpsetThread, host string) *domain.UpsetThreadDisplay {
-	var winners, losers, notables, dqs []*domain.UpsetThreadItemDisplay
-	for _, s := range upsetThread.Winners {
-		winners = append(winners, toLineItemDisplay(s))
+// bySurprise orders the items least likely win first when the output ranks
+// by surprise. Sets that were not rated come last, in their stored order.
+func bySurprise(items []domain.UpsetThreadItem, options *DisplayOptions) []domain.UpsetThreadItem {
+	if !options.RankBySurprise {
+		return items
 	}
-	for _, s := range upsetThread.Losers {
-		losers = append(losers, toLineItemDisplay(s))
+	winProbability := func(item domain.UpsetThreadItem) float64 {
+		if item.WinProbability == 0 {
+			return math.Inf(1)
+		}
+		return item.WinProbability
 	}
-	for _, s := range upsetThread.Notables {
-		notables = append(notables, toLineItemDisplay(s))
+	res := slices.Clone(items)
+	slices.SortStableFunc(res, func(i, j domain.UpsetThreadItem) int {
+		return cmp.Compare(winProbability(i), winProbability(j))
+	})
+	return res
+}
+
+// CharacterHighlights is the number of characters highlighted in a thread.
+const CharacterHighlights = 3
+
+// characterHighlights describes the characters that scored the most upsets
+// in the thread.
+func characterHighlights(upsetThread *domain.UpsetThread) []string {
+	var res []string
+	for _, stats := range domain.NewCharacterReport("", []*domain.UpsetThread{upsetThread}).Characters {
+		if len(res) == CharacterHighlights || stats.UpsetsWon == 0 {
+			break
+		}
+		upsets := "upsets"
+		if stats.UpsetsWon == 1 {
+			upsets = "upset"
+		}
+		res = append(res, fmt.Sprintf("%s players scored %d %s, average Upset Factor %.1f", stats.Character, stats.UpsetsWon, upsets, stats.AverageUpsetFactor))
 	}
-	for _, s := range upsetThread.DQs {
-		dqs = append(dqs, toDQLineItemDisplay(s))
+	return res
+}
+
+func ToDisplayWithOptions(upsetThread *domain.UpsetThread, host string, options *DisplayOptions) *domain.UpsetThreadDisplay {
+	toLine := options.LineFormat.toLineItemDisplay
+	grandFinals := toItemDisplays(upsetThread.GrandFinals, upsetThread.Slug, options, toLine)
+	winners := toItemDisplays(bySurprise(upsetThread.Winners, options), upsetThread.Slug, options, toLine)
+	losers := toItemDisplays(bySurprise(upsetThread.Losers, options), upsetThread.Slug, options, toLine)
+	pools := toItemDisplays(bySurprise(upsetThread.Pools, options), upsetThread.Slug, options, toLine)
+	notables := toItemDisplays(bySurprise(upsetThread.Notables, options), upsetThread.Slug, options, toLine)
+	dqs := toItemDisplays(upsetThread.DQs, upsetThread.Slug, options, toDQLineItemDisplay)
+	watched := toItemDisplays(bySurprise(upsetThread.Watched, options), upsetThread.Slug, options, toLine)
+	timeFormat := upsetThread.TimeFormat
+	if timeFormat == "" {
+		timeFormat = DefaultTimeFormat
 	}
-	location, err := time.LoadLocation("America/Los_Angeles")
-	if err != nil {
-		log.Fatalf("Error while loading location. e=%s\n", err)
+	// The thread is as recent as the last poll of its event, or as the
+	// render when it was never polled, such as a thread loaded from a file.
+	currentTime := options.Now()
+	if upsetThread.LastUpdatedAt != 0 {
+		currentTime = time.Unix(int64(upsetThread.LastUpdatedAt), 0)
 	}
-	currentTime := time.Now().In(location)
-	lastUpdatedAt := currentTime.Format("01/02/2006 03:04pm MST")
+	currentTime = currentTime.In(getLocation(upsetThread.Timezone))
 	return &domain.UpsetThreadDisplay{
-		Host:          host,
-		Title:         upsetThread.Title,
-		Slug:          upsetThread.Slug,
-		LastUpdatedAt: lastUpdatedAt,
-		Winners:       winners,
-		Losers:        losers,
-		Notables:      notables,
-		DQs:           dqs,
+		Host:                   host,
+		Title:                  upsetThread.Title,
+		Slug:                   upsetThread.Slug,
+		LastUpdatedAt:          currentTime.Format(timeFormat),
+		LastUpdatedAtTimestamp: currentTime.Unix(),
+		GrandFinals:            grandFinals,
+		Winners:                winners,
+		Losers:                 losers,
+		Pools:                  pools,
+		Notables:               notables,
+		DQs:                    dqs,
+		Watched:                watched,
+		Characters:             characterHighlights(upsetThread),
 	}
 }
@@ -0,0 +1,164 @@
+package mapper
+
+import (
+	"gg/domain"
+	"slices"
+	"testing"
+	"time"
+)
+
+type lastUpdatedTestCase struct {
+	name                 string
+	timezone, timeFormat string
+	expected             string
+}
+
+var lastUpdatedTestCases = []lastUpdatedTestCase{
+	{"Default timezone and format", "", "", "07/31/2023 12:30am PDT"},
+	{"Tournament timezone", "Europe/Berlin", "", "07/31/2023 09:30am CEST"},
+	{"Custom format", "Europe/Berlin", "02.01.2006 15:04", "31.07.2023 09:30"},
+	{"Unknown timezone falls back to default", "Europe/Nowhere", "", "07/31/2023 12:30am PDT"},
+}
+
+func TestToDisplayLastUpdatedAt(t *testing.T) {
+	now := time.Date(2023, 7, 31, 7, 30, 0, 0, time.UTC)
+	options := DefaultDisplayOptions()
+	options.Now = func() time.Time { return now }
+	for _, tc := range lastUpdatedTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			upsetThread := &domain.UpsetThread{Timezone: tc.timezone, TimeFormat: tc.timeFormat}
+			res := ToDisplayWithOptions(upsetThread, "", options)
+			if res.LastUpdatedAt != tc.expected {
+				t.Errorf("Expected %s, got %s", tc.expected, res.LastUpdatedAt)
+			}
+			if res.LastUpdatedAtTimestamp != now.Unix() {
+				t.Errorf("Expected timestamp %v, got %v", now.Unix(), res.LastUpdatedAtTimestamp)
+			}
+		})
+	}
+
+	polledAt := now.Add(-time.Hour)
+	upsetThread := &domain.UpsetThread{Timezone: "UTC", LastUpdatedAt: int(polledAt.Unix())}
+	if res := ToDisplayWithOptions(upsetThread, "", options); res.LastUpdatedAt != "07/31/2023 06:30am UTC" || res.LastUpdatedAtTimestamp != polledAt.Unix() {
+		t.Errorf("Expected the time of the last poll, got %s", res.LastUpdatedAt)
+	}
+}
+
+type linksTestCase struct {
+	name                string
+	item                domain.UpsetThreadItem
+	expectedUrl, vodUrl string
+}
+
+var linksTestCases = []linksTestCase{
+	{"Set and VOD", domain.UpsetThreadItem{Id: "1", VodUrl: "https://youtu.be/vod"}, "https://www.start.gg/tournament/sample/event/sample/set/1", "https://youtu.be/vod"},
+	{"Without ID", domain.UpsetThreadItem{}, "", ""},
+	{"Unsafe VOD", domain.UpsetThreadItem{Id: "2", VodUrl: "javascript:alert(1)"}, "https://www.start.gg/tournament/sample/event/sample/set/2", ""},
+	{"Quote in host", domain.UpsetThreadItem{VodUrl: `https://x" onmouseover=alert(1) x="`}, "", ""},
+	{"Without host", domain.UpsetThreadItem{VodUrl: "https:///vod"}, "", ""},
+	{"Relative VOD", domain.UpsetThreadItem{VodUrl: "//youtu.be/vod"}, "", ""},
+}
+
+func TestToDisplayLinks(t *testing.T) {
+	for _, tc := range linksTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			upsetThread := &domain.UpsetThread{Slug: "tournament/sample/event/sample", Winners: []domain.UpsetThreadItem{tc.item}}
+			res := ToDisplay(upsetThread, "").Winners[0]
+			if res.Url != tc.expectedUrl || res.VodUrl != tc.vodUrl {
+				t.Errorf("Expected %s and %s, got %s and %s", tc.expectedUrl, tc.vodUrl, res.Url, res.VodUrl)
+			}
+		})
+	}
+}
+
+func TestToDisplayTagOnly(t *testing.T) {
+	score := "3-1"
+	upsetThread := &domain.UpsetThread{Winners: []domain.UpsetThreadItem{{
+		WinnersName: "FaZe | Sparg0", WinnersTag: "Sparg0", WinnersSeed: 1, Score: &score,
+		LosersName: "Sonix", LosersTag: "Sonix", LosersSeed: 2, IsWinnersBracket: true,
+	}}}
+	options := DefaultDisplayOptions()
+	options.TagOnly = true
+	expected := "Sparg0 (seed 1) 3-1 Sonix (seed 2)"
+	if res := ToDisplayWithOptions(upsetThread, "", options).Winners[0].Content; res != expected {
+		t.Errorf("Expected %s, got %s", expected, res)
+	}
+	if res := ToDisplay(upsetThread, "").Winners[0].Content; res != "FaZe | Sparg0 (seed 1) 3-1 Sonix (seed 2)" {
+		t.Errorf("Expected the full name by default, got %s", res)
+	}
+}
+
+func TestToDisplayPhases(t *testing.T) {
+	score := "3-1"
+	upsetThread := &domain.UpsetThread{Winners: []domain.UpsetThreadItem{
+		{WinnersName: "Sparg0", WinnersSeed: 9, Score: &score, LosersName: "Sonix", LosersSeed: 1, IsWinnersBracket: true, PhaseName: "Pools", PoolIdentifier: "A12"},
+		{WinnersName: "Tweek", WinnersSeed: 5, Score: &score, LosersName: "Mkleo", LosersSeed: 2, IsWinnersBracket: true, PhaseName: "Top 64"},
+	}}
+	options := DefaultDisplayOptions()
+	options.PhaseLabels = true
+	res := ToDisplayWithOptions(upsetThread, "", options).Winners
+	if len(res) != 2 || res[0].Content != "[Pools A12] Sparg0 (seed 9) 3-1 Sonix (seed 1)" || res[1].Content != "[Top 64] Tweek (seed 5) 3-1 Mkleo (seed 2)" {
+		t.Errorf("Expected lines labelled with their phase, got %+v", res)
+	}
+	options.Phases = []string{"top 64"}
+	res = ToDisplayWithOptions(upsetThread, "", options).Winners
+	if len(res) != 1 || res[0].Content != "[Top 64] Tweek (seed 5) 3-1 Mkleo (seed 2)" {
+		t.Errorf("Expected the Top 64 set only, got %+v", res)
+	}
+	if res := ToDisplay(upsetThread, "").Winners; len(res) != 2 || res[0].Content != "Sparg0 (seed 9) 3-1 Sonix (seed 1)" {
+		t.Errorf("Expected every phase without labels by default, got %+v", res)
+	}
+}
+
+func TestToDisplaySurprise(t *testing.T) {
+	score := "3-1"
+	upsetThread := &domain.UpsetThread{Winners: []domain.UpsetThreadItem{
+		{WinnersName: "Sparg0", WinnersSeed: 9, Score: &score, LosersName: "Sonix", LosersSeed: 1, IsWinnersBracket: true, UpsetFactor: 5, WinProbability: 0.4},
+		{WinnersName: "Tweek", WinnersSeed: 5, Score: &score, LosersName: "Mkleo", LosersSeed: 2, IsWinnersBracket: true, UpsetFactor: 3},
+		{WinnersName: "Zomba", WinnersSeed: 7, Score: &score, LosersName: "Light", LosersSeed: 4, IsWinnersBracket: true, UpsetFactor: 2, WinProbability: 0.125},
+	}}
+	res := ToDisplay(upsetThread, "").Winners
+	if len(res) != 3 || res[0].Content != "Sparg0 (seed 9) 3-1 Sonix (seed 1) - Upset Factor 5 (40% to win)" || res[1].Content != "Tweek (seed 5) 3-1 Mkleo (seed 2) - Upset Factor 3" {
+		t.Errorf("Expected lines by upset factor with their win probability, got %+v", res)
+	}
+	options := DefaultDisplayOptions()
+	options.RankBySurprise = true
+	res = ToDisplayWithOptions(upsetThread, "", options).Winners
+	if len(res) != 3 || res[0].Content != "Zomba (seed 7) 3-1 Light (seed 4) - Upset Factor 2 (12% to win)" || res[1].UpsetFactor != 5 || res[2].UpsetFactor != 3 {
+		t.Errorf("Expected the least likely win first and the unrated set last, got %+v", res)
+	}
+}
+
+func TestToDisplayHeadToHead(t *testing.T) {
+	score := "3-1"
+	upsetThread := &domain.UpsetThread{Winners: []domain.UpsetThreadItem{
+		{WinnersName: "Sparg0", WinnersSeed: 9, Score: &score, LosersName: "Sonix", LosersSeed: 1, IsWinnersBracket: true, UpsetFactor: 5, HeadToHead: &domain.HeadToHead{Losses: 3}},
+		{WinnersName: "Tweek", WinnersSeed: 5, Score: &score, LosersName: "Mkleo", LosersSeed: 2, IsWinnersBracket: true, UpsetFactor: 3, HeadToHead: &domain.HeadToHead{Wins: 2, Losses: 5}},
+		{WinnersName: "Zomba", WinnersSeed: 7, Score: &score, LosersName: "Light", LosersSeed: 4, IsWinnersBracket: true, UpsetFactor: 2, HeadToHead: &domain.HeadToHead{}},
+	}}
+	res := ToDisplay(upsetThread, "").Winners
+	expected := []string{
+		"Sparg0 (seed 9) 3-1 Sonix (seed 1) - Upset Factor 5 - first win after 3 losses",
+		"Tweek (seed 5) 3-1 Mkleo (seed 2) - Upset Factor 3 - previously 2-5",
+		"Zomba (seed 7) 3-1 Light (seed 4) - Upset Factor 2 - first meeting",
+	}
+	for i, line := range expected {
+		if len(res) != len(expected) || res[i].Content != line {
+			t.Fatalf("Expected %q, got %+v", line, res)
+		}
+	}
+}
+
+func TestToDisplayCharacters(t *testing.T) {
+	score := "3-1"
+	upsetThread := &domain.UpsetThread{Winners: []domain.UpsetThreadItem{
+		{Id: "1", WinnersCharacters: "Steve", LosersCharacters: "Cloud", Score: &score, UpsetFactor: 6, CompletedAt: 1},
+		{Id: "2", WinnersCharacters: "Steve", LosersCharacters: "Cloud", Score: &score, UpsetFactor: 3, CompletedAt: 2},
+		{Id: "3", WinnersCharacters: "Kazuya", LosersCharacters: "Cloud", Score: &score, UpsetFactor: 2, CompletedAt: 3},
+	}}
+	res := ToDisplay(upsetThread, "").Characters
+	expected := []string{"Steve players scored 2 upsets, average Upset Factor 4.5", "Kazuya players scored 1 upset, average Upset Factor 2.0"}
+	if !slices.Equal(res, expected) {
+		t.Errorf("Expected %v, got %v", expected, res)
+	}
+}
@@ -0,0 +1,74 @@
+package mapper
+
+import (
+	"gg/domain"
+	"sort"
+	"time"
+)
+
+// MaxFeedEntries caps a feed to its most recent entries.
+const MaxFeedEntries = 200
+
+func toFeedEntry(upsetThread *domain.UpsetThread, item domain.UpsetThreadItem, category string) domain.FeedEntry {
+	return domain.FeedEntry{
+		Id:       SetUrl(upsetThread.Slug, item.Id),
+		Title:    toLineItemDisplay(item).Content,
+		Updated:  time.Unix(int64(item.CompletedAt), 0).UTC().Format(time.RFC3339),
+		Link:     domain.FeedLink{Href: SetUrl(upsetThread.Slug, item.Id)},
+		Category: domain.FeedCategory{Term: category},
+		Summary:  upsetThread.Title,
+	}
+}
+
+// ToFeed builds an Atom feed with an entry for every grand final, upset,
+// notable and DQ of the given upset threads, newest first. Sets without a
+// completion time are left out, as they have no time to be listed by. Entry
+// IDs are derived from the set ID so that feed readers recognise entries
+// across polls.
+func ToFeed(id, title string, upsetThreads []*domain.UpsetThread, now time.Time) *domain.Feed {
+	type entry struct {
+		completedAt int
+		feedEntry   domain.FeedEntry
+	}
+	var entries []entry
+	for _, upsetThread := range upsetThreads {
+		for category, items := range map[string][]domain.UpsetThreadItem{
+			"grandfinals": upsetThread.GrandFinals,
+			"winners":     upsetThread.Winners,
+			"losers":      upsetThread.Losers,
+			"pools":       upsetThread.Pools,
+			"notables":    upsetThread.Notables,
+			"dqs":         upsetThread.DQs,
+		} {
+			for _, item := range items {
+				if item.CompletedAt == 0 {
+					continue
+				}
+				entries = append(entries, entry{item.CompletedAt, toFeedEntry(upsetThread, item, category)})
+			}
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].completedAt != entries[j].completedAt {
+			return entries[i].completedAt > entries[j].completedAt
+		}
+		return entries[i].feedEntry.Id < entries[j].feedEntry.Id
+	})
+	if len(entries) > MaxFeedEntries {
+		entries = entries[:MaxFeedEntries]
+	}
+	updated := now
+	if len(entries) > 0 {
+		updated = time.Unix(int64(entries[0].completedAt), 0)
+	}
+	feed := &domain.Feed{
+		Id:      id,
+		Title:   title,
+		Updated: updated.UTC().Format(time.RFC3339),
+		Author:  domain.FeedAuthor{Name: "gg"},
+	}
+	for _, e := range entries {
+		feed.Entries = append(feed.Entries, e.feedEntry)
+	}
+	return feed
+}
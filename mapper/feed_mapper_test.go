@@ -0,0 +1,53 @@
+package mapper
+
+import (
+	"gg/domain"
+	"testing"
+	"time"
+)
+
+func TestToFeed(t *testing.T) {
+	score := "2-0"
+	events := []*domain.UpsetThread{
+		{
+			Title:   "Supernova",
+			Slug:    "tournament/supernova/event/singles",
+			Winners: []domain.UpsetThreadItem{{Id: "1", WinnersName: "Mar", WinnersSeed: 62, Score: &score, LosersName: "Zomba", LosersSeed: 3, IsWinnersBracket: true, UpsetFactor: 9, CompletedAt: 100}},
+			DQs:     []domain.UpsetThreadItem{{Id: "2", WinnersName: "Sonix", LosersName: "Tweek", CompletedAt: 300}},
+			Other:   []domain.UpsetThreadItem{{Id: "3", CompletedAt: 400}},
+		},
+		{
+			Title:    "Genesis",
+			Slug:     "tournament/genesis/event/singles",
+			Notables: []domain.UpsetThreadItem{{Id: "4", WinnersName: "Light", LosersName: "Glutonny", CompletedAt: 200}, {Id: "5", WinnersName: "MkLeo", LosersName: "Tweek"}},
+		},
+	}
+	feed := ToFeed("urn:gg:events", "Upsets", events, time.Unix(0, 0))
+	if feed.Updated != "1970-01-01T00:05:00Z" {
+		t.Errorf("Expected feed updated at the newest entry, got %s", feed.Updated)
+	}
+	expectedIds := []string{
+		"https://www.start.gg/tournament/supernova/event/singles/set/2",
+		"https://www.start.gg/tournament/genesis/event/singles/set/4",
+		"https://www.start.gg/tournament/supernova/event/singles/set/1",
+	}
+	if len(feed.Entries) != len(expectedIds) {
+		t.Fatalf("Expected %d entries, got %d", len(expectedIds), len(feed.Entries))
+	}
+	for i, id := range expectedIds {
+		if feed.Entries[i].Id != id {
+			t.Errorf("Expected entry %d to be %s, got %s", i, id, feed.Entries[i].Id)
+		}
+	}
+	upset := feed.Entries[2]
+	if upset.Title != "Mar (seed 62) 2-0 Zomba (seed 3) - Upset Factor 9" || upset.Updated != "1970-01-01T00:01:40Z" || upset.Category.Term != "winners" || upset.Summary != "Supernova" {
+		t.Errorf("Unexpected entry %+v", upset)
+	}
+}
+
+func TestToFeedWithoutEntries(t *testing.T) {
+	feed := ToFeed("urn:gg:events", "Upsets", nil, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+	if feed.Updated != "2024-01-02T03:04:05Z" || len(feed.Entries) != 0 {
+		t.Errorf("Expected an empty feed updated now, got %+v", feed)
+	}
+}
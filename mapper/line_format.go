@@ -0,0 +1,108 @@
+package mapper
+
+import (
+	"fmt"
+	"gg/domain"
+	"strings"
+	"text/template"
+)
+
+const (
+	DefaultLineTemplate = `{{.WinnersName}}{{with .WinnersCharacters}} ({{.}}){{end}} (seed {{.WinnersSeed}}) {{.Score}} ` +
+		`{{.LosersName}}{{with .LosersCharacters}} ({{.}}){{end}} (seed {{.LosersSeed}})` +
+		`{{if .BracketType.IsPoolStage}}, went {{.LosersRecord}} in {{.BracketType.StageName}}` +
+		`{{else if not .IsWinnersBracket}}, out at {{ordinal .LosersPlacement}}{{end}}` +
+		`{{with .GrandFinal}} - {{$.WinnersName}} {{.}}{{end}}` +
+		`{{if gt .UpsetFactor 0}} - Upset Factor {{.UpsetFactor}}{{end}}` +
+		`{{with .WinChance}} ({{.}} to win){{end}}` +
+		`{{with .HeadToHeadNote}} - {{.}}{{end}}`
+	DefaultEmphasisTemplate = `{{ge .UpsetFactor 4}}`
+)
+
+var lineFuncs = template.FuncMap{
+	"ordinal": getOrdinal,
+}
+
+// LineFormat renders a single upset thread item. Line has access to every
+// field of domain.UpsetThreadItem and Emphasis decides whether the line is
+// emphasised by rendering "true".
+type LineFormat struct {
+	line     *template.Template
+	emphasis *template.Template
+}
+
+var sampleUpsetThreadItem = domain.UpsetThreadItem{
+	Id:                "1",
+	WinnersName:       "Sponsor | Winner",
+	WinnersPrefix:     "Sponsor",
+	WinnersTag:        "Winner",
+	WinnersCharacters: "Steve",
+	WinnersSeed:       33,
+	Score:             new(string),
+	LosersName:        "Loser",
+	LosersTag:         "Loser",
+	LosersCharacters:  "Kazuya",
+	LosersSeed:        2,
+	LosersPlacement:   25,
+	UpsetFactor:       7,
+	WinProbability:    0.12,
+	HeadToHead:        &domain.HeadToHead{Losses: 3},
+	Category:          "losers",
+}
+
+func parseLineTemplate(name, text string) (*template.Template, error) {
+	t, err := template.New(name).Funcs(lineFuncs).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.Execute(&strings.Builder{}, sampleUpsetThreadItem); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// NewLineFormat parses the line and emphasis templates, falling back to the
+// defaults for whichever is empty.
+func NewLineFormat(line, emphasis string) (*LineFormat, error) {
+	if line == "" {
+		line = DefaultLineTemplate
+	}
+	if emphasis == "" {
+		emphasis = DefaultEmphasisTemplate
+	}
+	lineTemplate, err := parseLineTemplate("line", line)
+	if err != nil {
+		return nil, fmt.Errorf("invalid line template: %w", err)
+	}
+	emphasisTemplate, err := parseLineTemplate("emphasis", emphasis)
+	if err != nil {
+		return nil, fmt.Errorf("invalid emphasis template: %w", err)
+	}
+	return &LineFormat{line: lineTemplate, emphasis: emphasisTemplate}, nil
+}
+
+func mustNewLineFormat(line, emphasis string) *LineFormat {
+	res, err := NewLineFormat(line, emphasis)
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
+var DefaultLineFormat = mustNewLineFormat(DefaultLineTemplate, DefaultEmphasisTemplate)
+
+func (f *LineFormat) render(t *template.Template, item domain.UpsetThreadItem) string {
+	var sb strings.Builder
+	if err := t.Execute(&sb, item); err != nil {
+		return fmt.Sprintf("error rendering %s: %s", t.Name(), err)
+	}
+	return sb.String()
+}
+
+func (f *LineFormat) toLineItemDisplay(item domain.UpsetThreadItem) *domain.UpsetThreadItemDisplay {
+	return &domain.UpsetThreadItemDisplay{
+		Content:     f.render(f.line, item),
+		Bold:        strings.TrimSpace(f.render(f.emphasis, item)) == "true",
+		UpsetFactor: item.UpsetFactor,
+	}
+}
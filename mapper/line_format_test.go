@@ -0,0 +1,100 @@
+package mapper
+
+import (
+	"gg/domain"
+	"strings"
+	"testing"
+)
+
+type lineFormatTestCase struct {
+	name             string
+	line, emphasis   string
+	item             domain.UpsetThreadItem
+	expected         string
+	expectedEmphasis bool
+}
+
+var threeTwo, twoZero = "3-2", "2-0"
+
+var winnersItem = domain.UpsetThreadItem{
+	WinnersName:       "Mar",
+	WinnersCharacters: "Bayonetta",
+	WinnersSeed:       62,
+	Score:             &threeTwo,
+	LosersName:        "LG | Zomba",
+	LosersCharacters:  "R.O.B.",
+	IsWinnersBracket:  true,
+	LosersSeed:        3,
+	UpsetFactor:       9,
+}
+
+var losersItem = domain.UpsetThreadItem{
+	WinnersName:     "Sonix",
+	WinnersSeed:     3,
+	Score:           &twoZero,
+	LosersName:      "Tweek",
+	LosersSeed:      5,
+	LosersPlacement: 9,
+}
+
+var lineFormatTestCases = []lineFormatTestCase{
+	{
+		"Default winners line",
+		"",
+		"",
+		winnersItem,
+		"Mar (Bayonetta) (seed 62) 3-2 LG | Zomba (R.O.B.) (seed 3) - Upset Factor 9",
+		true,
+	},
+	{
+		"Default losers line",
+		"",
+		"",
+		losersItem,
+		"Sonix (seed 3) 2-0 Tweek (seed 5), out at 9th",
+		false,
+	},
+	{
+		"Upset factor first without characters",
+		"[{{.UpsetFactor}}] {{.WinnersName}} [{{.WinnersSeed}}] {{.Score}} {{.LosersName}} [{{.LosersSeed}}]",
+		"{{and .IsWinnersBracket (ge .UpsetFactor 8)}}",
+		winnersItem,
+		"[9] Mar [62] 3-2 LG | Zomba [3]",
+		true,
+	},
+	{
+		"Custom emphasis",
+		"{{.WinnersName}} out at {{ordinal .LosersPlacement}}",
+		"{{ge .UpsetFactor 10}}",
+		losersItem,
+		"Sonix out at 9th",
+		false,
+	},
+}
+
+func TestLineFormat(t *testing.T) {
+	for _, tc := range lineFormatTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			lineFormat, err := NewLineFormat(tc.line, tc.emphasis)
+			if err != nil {
+				t.Fatalf("Expected no error, got %s", err)
+			}
+			res := lineFormat.toLineItemDisplay(tc.item)
+			if res.Content != tc.expected {
+				t.Errorf("Expected %s, got %s", tc.expected, res.Content)
+			}
+			if res.Bold != tc.expectedEmphasis {
+				t.Errorf("Expected emphasis %t, got %t", tc.expectedEmphasis, res.Bold)
+			}
+		})
+	}
+}
+
+func TestInvalidLineFormat(t *testing.T) {
+	if _, err := NewLineFormat("{{.WinnersName", ""); err == nil || !strings.Contains(err.Error(), "line template") {
+		t.Errorf("Expected line template error, got %v", err)
+	}
+	if _, err := NewLineFormat("", "{{.Winner}}"); err == nil || !strings.Contains(err.Error(), "emphasis template") {
+		t.Errorf("Expected emphasis template error, got %v", err)
+	}
+}
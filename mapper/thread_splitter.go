@@ -0,0 +1,157 @@
+package mapper
+
+import (
+	"fmt"
+	"gg/domain"
+	"sort"
+	"unicode/utf8"
+)
+
+const (
+	RedditPostLimit    = 40000
+	RedditCommentLimit = 10000
+)
+
+// maxParts is assumed while measuring parts, with comments measured as a
+// middle part, so that the final numbering can only make a part shorter.
+const maxParts = 99
+
+type RenderFunc func(display *domain.UpsetThreadDisplay) (string, error)
+
+// ThreadSplitter renders an upset thread as a post followed by as many
+// comments as are needed to stay under Reddit's length limits.
+type ThreadSplitter struct {
+	post, comment           RenderFunc
+	postLimit, commentLimit int
+}
+
+func NewThreadSplitter(post, comment RenderFunc, postLimit, commentLimit int) *ThreadSplitter {
+	return &ThreadSplitter{
+		post:         post,
+		comment:      comment,
+		postLimit:    postLimit,
+		commentLimit: commentLimit,
+	}
+}
+
+type splitItem struct {
+	section, index int
+	item           *domain.UpsetThreadItemDisplay
+}
+
+func sections(display *domain.UpsetThreadDisplay) [][]*domain.UpsetThreadItemDisplay {
+	return [][]*domain.UpsetThreadItemDisplay{display.GrandFinals, display.Winners, display.Losers, display.Pools, display.Notables, display.DQs, display.Watched}
+}
+
+// withItems copies the display keeping only the given items, in their
+// original section and order.
+func withItems(display *domain.UpsetThreadDisplay, items []splitItem, part, parts int) *domain.UpsetThreadDisplay {
+	sorted := append([]splitItem(nil), items...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].section != sorted[j].section {
+			return sorted[i].section < sorted[j].section
+		}
+		return sorted[i].index < sorted[j].index
+	})
+	grouped := make([][]*domain.UpsetThreadItemDisplay, len(sections(display)))
+	for _, s := range sorted {
+		grouped[s.section] = append(grouped[s.section], s.item)
+	}
+	res := *display
+	res.GrandFinals, res.Winners, res.Losers, res.Pools, res.Notables, res.DQs, res.Watched = grouped[0], grouped[1], grouped[2], grouped[3], grouped[4], grouped[5], grouped[6]
+	res.Part, res.Parts = part, parts
+	return &res
+}
+
+func (s *ThreadSplitter) fits(render RenderFunc, display *domain.UpsetThreadDisplay, limit int) (bool, error) {
+	content, err := render(display)
+	if err != nil {
+		return false, err
+	}
+	return utf8.RuneCountInString(content) <= limit, nil
+}
+
+// Split returns the post followed by its comments. The post keeps the sets
+// with the highest upset factors and the rest overflow into comments by
+// section, in their original order.
+func (s *ThreadSplitter) Split(display *domain.UpsetThreadDisplay) ([]string, error) {
+	var items []splitItem
+	for section, sectionItems := range sections(display) {
+		for index, item := range sectionItems {
+			items = append(items, splitItem{section: section, index: index, item: item})
+		}
+	}
+	post, err := s.post(withItems(display, items, 1, 1))
+	if err != nil {
+		return nil, err
+	}
+	if utf8.RuneCountInString(post) <= s.postLimit {
+		return []string{post}, nil
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].item.UpsetFactor > items[j].item.UpsetFactor
+	})
+	// The rendered length only grows with the number of items kept, so
+	// search for the largest prefix of the prioritised items that fits.
+	var searchErr error
+	kept := sort.Search(len(items)+1, func(n int) bool {
+		ok, err := s.fits(s.post, withItems(display, items[:n], 1, maxParts), s.postLimit)
+		if err != nil {
+			searchErr = err
+		}
+		return !ok
+	}) - 1
+	if searchErr != nil {
+		return nil, searchErr
+	}
+	if kept < 0 {
+		return nil, fmt.Errorf("thread header is longer than %d characters", s.postLimit)
+	}
+
+	overflow := withItems(display, items[kept:], 0, 0)
+	var comments [][]splitItem
+	var current []splitItem
+	for section, sectionItems := range sections(overflow) {
+		for index, item := range sectionItems {
+			entry := splitItem{section: section, index: index, item: item}
+			for {
+				next := append(append([]splitItem(nil), current...), entry)
+				ok, err := s.fits(s.comment, withItems(display, next, maxParts-1, maxParts), s.commentLimit)
+				if err != nil {
+					return nil, err
+				}
+				if ok {
+					current = next
+					break
+				}
+				if len(current) == 0 {
+					return nil, fmt.Errorf("line is longer than %d characters: %s", s.commentLimit, item.Content)
+				}
+				comments = append(comments, current)
+				current = nil
+			}
+		}
+	}
+	if len(current) > 0 {
+		comments = append(comments, current)
+	}
+	parts := len(comments) + 1
+	if parts > maxParts {
+		return nil, fmt.Errorf("thread needs %d parts, more than %d", parts, maxParts)
+	}
+
+	post, err = s.post(withItems(display, items[:kept], 1, parts))
+	if err != nil {
+		return nil, err
+	}
+	res := []string{post}
+	for i, comment := range comments {
+		content, err := s.comment(withItems(display, comment, i+2, parts))
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, content)
+	}
+	return res, nil
+}
@@ -0,0 +1,78 @@
+package mapper
+
+import (
+	"fmt"
+	"gg/domain"
+	"strings"
+	"testing"
+)
+
+func renderSections(header string) RenderFunc {
+	return func(display *domain.UpsetThreadDisplay) (string, error) {
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "%s %d/%d\n", header, display.Part, display.Parts)
+		for i, section := range sections(display) {
+			for _, item := range section {
+				fmt.Fprintf(&sb, "%d:%s\n", i, item.Content)
+			}
+		}
+		return sb.String(), nil
+	}
+}
+
+func item(content string, upsetFactor int) *domain.UpsetThreadItemDisplay {
+	return &domain.UpsetThreadItemDisplay{Content: content, UpsetFactor: upsetFactor}
+}
+
+type threadSplitterTestCase struct {
+	name                    string
+	postLimit, commentLimit int
+	expected                []string
+}
+
+var splitterDisplay = &domain.UpsetThreadDisplay{
+	Winners: []*domain.UpsetThreadItemDisplay{item("w1", 2), item("w2", 9)},
+	Losers:  []*domain.UpsetThreadItemDisplay{item("l1", 5), item("l2", 1)},
+	DQs:     []*domain.UpsetThreadItemDisplay{item("dq", 0)},
+}
+
+var threadSplitterTestCases = []threadSplitterTestCase{
+	{
+		"Fits in a single post",
+		100,
+		100,
+		[]string{"post 1/1\n1:w1\n1:w2\n2:l1\n2:l2\n5:dq\n"},
+	},
+	{
+		"Highest upset factors stay in the post",
+		len("post 1/99\n1:w2\n2:l1\n"),
+		len("comment 98/99\n1:w1\n2:l2\n"),
+		[]string{
+			"post 1/3\n1:w2\n2:l1\n",
+			"comment 2/3\n1:w1\n2:l2\n",
+			"comment 3/3\n5:dq\n",
+		},
+	},
+}
+
+func TestThreadSplitter(t *testing.T) {
+	for _, tc := range threadSplitterTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			splitter := NewThreadSplitter(renderSections("post"), renderSections("comment"), tc.postLimit, tc.commentLimit)
+			res, err := splitter.Split(splitterDisplay)
+			if err != nil {
+				t.Fatalf("Expected no error, got %s", err)
+			}
+			if fmt.Sprint(res) != fmt.Sprint(tc.expected) {
+				t.Errorf("Expected %q, got %q", tc.expected, res)
+			}
+		})
+	}
+}
+
+func TestThreadSplitterLineTooLong(t *testing.T) {
+	splitter := NewThreadSplitter(renderSections("post"), renderSections("comment"), 20, 5)
+	if _, err := splitter.Split(splitterDisplay); err == nil {
+		t.Errorf("Expected an error for lines longer than the comment limit")
+	}
+}
@@ -9,6 +9,7 @@ func SetToUpsetThreadItem(set domain.Set, category string) domain.UpsetThreadIte
 		WinnersCharacters: set.GetWinnerCharacterSelections(),
 		WinnersSeed:       set.Winner.InitialSeed,
 		Score:             set.Score,
+		Outcome:           set.Outcome,
 		LosersName:        set.Loser.Name,
 		LosersCharacters:  set.GetLoserCharacterSelections(),
 		IsWinnersBracket:  set.IsWinnersBracket(),
@@ -17,5 +18,22 @@ func SetToUpsetThreadItem(set domain.Set, category string) domain.UpsetThreadIte
 		UpsetFactor:       set.UpsetFactor,
 		CompletedAt:       set.CompletedAt,
 		Category:          category,
+		VodUrl:            set.VodUrl,
+		StreamName:        set.StreamName,
+		StreamSource:      set.StreamSource,
+		WinnersPlayerIds:  set.Winner.PlayerIds,
+		LosersPlayerIds:   set.Loser.PlayerIds,
+		WinnersPrefix:     set.Winner.Prefix,
+		WinnersTag:        set.Winner.Tag,
+		LosersPrefix:      set.Loser.Prefix,
+		LosersTag:         set.Loser.Tag,
+		GrandFinal:        set.GrandFinal,
+		BracketType:       set.BracketType,
+		WinnersRecord:     set.Winner.Record,
+		LosersRecord:      set.Loser.Record,
+		PhaseName:         set.PhaseName,
+		PoolIdentifier:    set.PoolIdentifier,
+		WinnersPlacement:  set.Winner.Placement,
+		Notable:           set.Notable,
 	}
 }
@@ -0,0 +1,23 @@
+package mapper
+
+import (
+	"fmt"
+	"gg/domain"
+)
+
+// ToWatchlistMessage describes a watched set for a webhook notification.
+func ToWatchlistMessage(watchlist domain.Watchlist, upsetThread *domain.UpsetThread, item domain.UpsetThreadItem) string {
+	return fmt.Sprintf("%s: %s (%s)\n%s", watchlist.Name, toLineItemDisplay(item).Content, upsetThread.Title, SetUrl(upsetThread.Slug, item.Id))
+}
+
+func ToWatchlistDisplay(watchlist domain.Watchlist, upsetThreads []*domain.UpsetThread, host string, options *DisplayOptions) *domain.WatchlistDisplay {
+	res := &domain.WatchlistDisplay{
+		Host: host,
+		Id:   watchlist.Id,
+		Name: watchlist.Name,
+	}
+	for _, upsetThread := range upsetThreads {
+		res.Events = append(res.Events, ToDisplayWithOptions(upsetThread, host, options))
+	}
+	return res
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"gg/service"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var (
+	overlaySections = []string{"grandfinals", "winners", "losers", "pools", "notables", "dqs"}
+	overlayThemes   = []string{"dark", "light"}
+)
+
+// overlaySettings are read from the overlay page's query string so each
+// browser source can be configured in OBS alone.
+type overlaySettings struct {
+	Slug           string
+	MinUpsetFactor int
+	Sections       []string
+	Theme          string
+	// Duration is how many seconds a new upset stays on screen.
+	Duration int
+	Test     bool
+}
+
+func parseOverlaySettings(query url.Values) (*overlaySettings, error) {
+	settings := &overlaySettings{
+		Slug:           query.Get("slug"),
+		MinUpsetFactor: 1,
+		Sections:       []string{"winners", "losers"},
+		Theme:          "dark",
+		Duration:       8,
+		Test:           query.Get("test") == "1" || query.Get("test") == "true",
+	}
+	var errs []error
+	if s := query.Get("min"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("min must be a number, got %q", s))
+		}
+		settings.MinUpsetFactor = n
+	}
+	if s := query.Get("sections"); s != "" {
+		settings.Sections = strings.Split(s, ",")
+		for _, section := range settings.Sections {
+			if !slices.Contains(overlaySections, section) {
+				errs = append(errs, fmt.Errorf("sections must be some of %s, got %q", strings.Join(overlaySections, ", "), section))
+			}
+		}
+	}
+	if s := query.Get("theme"); s != "" {
+		if !slices.Contains(overlayThemes, s) {
+			errs = append(errs, fmt.Errorf("theme must be one of %s, got %q", strings.Join(overlayThemes, ", "), s))
+		}
+		settings.Theme = s
+	}
+	if s := query.Get("duration"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 || n > 60 {
+			errs = append(errs, fmt.Errorf("duration must be between 1 and 60 seconds, got %q", s))
+		}
+		settings.Duration = n
+	}
+	return settings, errors.Join(errs...)
+}
+
+type OverlayHandler struct {
+	tracker   service.TrackerInterface
+	service   service.ServiceInterface
+	templates *templates
+}
+
+func handleOverlay(tracker service.TrackerInterface, service service.ServiceInterface, templates *templates) {
+	h := &OverlayHandler{tracker: tracker, service: service, templates: templates}
+	http.HandleFunc("GET /overlay", h.page)
+	http.HandleFunc("GET /overlay/sample.json", h.sample)
+}
+
+func (h *OverlayHandler) page(w http.ResponseWriter, r *http.Request) {
+	settings, err := parseOverlaySettings(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !settings.Test {
+		if _, err := h.tracker.GetEvent(settings.Slug); err != nil {
+			http.Error(w, "Not found", http.StatusNotFound)
+			return
+		}
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	h.templates.overlayHTML.Execute(w, settings)
+}
+
+func (h *OverlayHandler) sample(w http.ResponseWriter, r *http.Request) {
+	upsetThread, err := h.service.GetSampleUpsetThread()
+	if err != nil {
+		log.Printf("Error while building sample. e=%s\n", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	format, _ := h.templates.formats.Get("json")
+	parts, err := format.Format(upsetThread, r.Host)
+	if err != nil {
+		log.Printf("Error while formatting sample. e=%s\n", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", format.ContentType())
+	io.WriteString(w, strings.Join(parts, "\n"))
+}
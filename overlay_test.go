@@ -0,0 +1,28 @@
+package main
+
+import (
+	"net/url"
+	"slices"
+	"testing"
+)
+
+func TestParseOverlaySettings(t *testing.T) {
+	query, _ := url.ParseQuery("slug=tournament/sample/event/sample&min=4&sections=losers,dqs&theme=light&duration=5&test=1")
+	settings, err := parseOverlaySettings(query)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if settings.MinUpsetFactor != 4 || !slices.Equal(settings.Sections, []string{"losers", "dqs"}) || settings.Theme != "light" || settings.Duration != 5 || !settings.Test {
+		t.Errorf("Unexpected settings %+v", settings)
+	}
+
+	settings, err = parseOverlaySettings(url.Values{})
+	if err != nil || settings.MinUpsetFactor != 1 || settings.Theme != "dark" || settings.Test {
+		t.Errorf("Expected default settings, got %+v %v", settings, err)
+	}
+
+	query, _ = url.ParseQuery("min=x&sections=grand-finals&theme=neon&duration=0")
+	if _, err := parseOverlaySettings(query); err == nil {
+		t.Errorf("Expected invalid settings to fail")
+	}
+}
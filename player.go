@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"gg/service"
+	"log"
+	"net/http"
+)
+
+type PlayerHandler struct {
+	tracker   service.TrackerInterface
+	templates *templates
+}
+
+// handlePlayers registers the upset history, rating and head-to-head records
+// of players, looked up by start.gg player ID or gamer tag.
+func handlePlayers(tracker service.TrackerInterface, templates *templates) {
+	h := &PlayerHandler{tracker: tracker, templates: templates}
+	http.HandleFunc("GET /player/{player}", h.history)
+	http.HandleFunc("GET /player/{player}/rating", h.rating)
+	http.HandleFunc("GET /player/{player}/head-to-head/{opponent}", h.headToHead)
+}
+
+// history writes the player's upsets as a page, or as JSON for
+// ?format=json.
+func (h *PlayerHandler) history(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "html" && format != "json" {
+		http.Error(w, "unknown format "+format+", expected one of html, json", http.StatusBadRequest)
+		return
+	}
+	history, err := h.tracker.GetPlayerHistory(r.PathValue("player"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if format == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(history); err != nil {
+			log.Printf("Error while encoding player. player=%s e=%s\n", history.Player, err)
+		}
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := h.templates.playerHTML.Execute(w, history); err != nil {
+		log.Printf("Error while rendering player. player=%s e=%s\n", history.Player, err)
+	}
+}
+
+// rating writes the player's rating and its history as JSON.
+func (h *PlayerHandler) rating(w http.ResponseWriter, r *http.Request) {
+	rating, err := h.tracker.GetPlayerRating(r.PathValue("player"))
+	if err == service.ErrorPlayerNotRated {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(rating); err != nil {
+		log.Printf("Error while encoding rating. player=%s e=%s\n", rating.Player, err)
+	}
+}
+
+// headToHead writes the sets between the player and the opponent as JSON.
+func (h *PlayerHandler) headToHead(w http.ResponseWriter, r *http.Request) {
+	record, err := h.tracker.GetHeadToHead(r.PathValue("player"), r.PathValue("opponent"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(record); err != nil {
+		log.Printf("Error while encoding head-to-head. player=%s opponent=%s e=%s\n", record.Player, record.Opponent, err)
+	}
+}
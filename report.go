@@ -0,0 +1,35 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"text/template"
+)
+
+// reportFormat reads the format of a report from ?format=, either markdown,
+// the default, or json, and sets the matching content type.
+func reportFormat(w http.ResponseWriter, r *http.Request) (string, bool) {
+	format := r.URL.Query().Get("format")
+	switch format {
+	case "", "markdown":
+		w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+		return "markdown", true
+	case "json":
+		w.Header().Set("Content-Type", "application/json")
+		return format, true
+	}
+	http.Error(w, "unknown format "+format+", expected one of markdown, json", http.StatusBadRequest)
+	return "", false
+}
+
+// writeReport writes the report with its markdown template, or as JSON for
+// the json format.
+func writeReport(w io.Writer, t *template.Template, report any, format string) error {
+	if format == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+	return t.Execute(w, report)
+}
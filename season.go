@@ -0,0 +1,35 @@
+package main
+
+import (
+	"gg/service"
+	"log"
+	"net/http"
+)
+
+type SeasonHandler struct {
+	tracker   service.TrackerInterface
+	templates *templates
+}
+
+// handleSeasons registers the leaderboards of every season.
+func handleSeasons(tracker service.TrackerInterface, templates *templates) {
+	h := &SeasonHandler{tracker: tracker, templates: templates}
+	http.HandleFunc("GET /season/{id}", h.leaderboard)
+}
+
+// leaderboard writes the season's leaderboards in the format given by
+// ?format=, either markdown, the default, or json.
+func (h *SeasonHandler) leaderboard(w http.ResponseWriter, r *http.Request) {
+	format, ok := reportFormat(w, r)
+	if !ok {
+		return
+	}
+	leaderboard, err := h.tracker.GetSeasonLeaderboard(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+	if err := writeReport(w, h.templates.season, leaderboard, format); err != nil {
+		log.Printf("Error while rendering season. id=%s e=%s\n", leaderboard.Id, err)
+	}
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"gg/service"
+	"log"
+	"net/http"
+)
+
+type SeedingHandler struct {
+	tracker   service.TrackerInterface
+	templates *templates
+}
+
+// handleSeeding registers the seeding report of each event with stored sets.
+func handleSeeding(tracker service.TrackerInterface, templates *templates) {
+	http.Handle("GET /event/tournament/{tournament}/event/{event}/seeding", &SeedingHandler{tracker: tracker, templates: templates})
+}
+
+func (h *SeedingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	format, ok := reportFormat(w, r)
+	if !ok {
+		return
+	}
+	slug := eventSlug(r)
+	report, err := h.tracker.GetSeedingReport(slug)
+	if err != nil {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+	if err := writeReport(w, h.templates.seeding, report, format); err != nil {
+		log.Printf("Error while rendering seeding. slug=%s e=%s\n", slug, err)
+	}
+}
@@ -0,0 +1,25 @@
+package service
+
+import "gg/domain"
+
+// AllEventsTitle is the title of the character report of every event with
+// stored sets.
+const AllEventsTitle = "All events"
+
+// GetCharacterReport returns the character statistics of the tracked event,
+// or of every event with stored sets for AllEvents.
+func (t *Tracker) GetCharacterReport(slug string) (*domain.CharacterReport, error) {
+	if slug == AllEvents {
+		titles := t.eventTitles()
+		var upsetThreads []*domain.UpsetThread
+		for _, slug := range t.dbService.GetSetSlugs() {
+			upsetThreads = append(upsetThreads, t.storedUpsetThread(slug, titles[slug]))
+		}
+		return domain.NewCharacterReport(AllEventsTitle, upsetThreads), nil
+	}
+	upsetThread, err := t.GetUpsetThread(slug)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewCharacterReport(upsetThread.Title, []*domain.UpsetThread{upsetThread}), nil
+}
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"gg/domain"
+	"testing"
+)
+
+func TestTrackerCharacterReport(t *testing.T) {
+	var exported []*domain.UpsetThread
+	tracker := newTestTracker(&exported)
+	event := trackerEvent
+	event.Paused = true
+	tracker.AddEvent(event)
+	tracker.process(&event)
+
+	if _, err := tracker.GetCharacterReport("tournament/unknown/event/singles"); err != ErrorEventNotFound {
+		t.Errorf("Expected %s, got %v", ErrorEventNotFound, err)
+	}
+	res, err := tracker.GetCharacterReport(slug)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if res.Title != trackerEvent.Title || len(res.Characters) == 0 {
+		t.Errorf("Expected the characters of the event, got %v", res)
+	}
+	tracker.RemoveEvent(slug)
+	all, err := tracker.GetCharacterReport(AllEvents)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if all.Title != AllEventsTitle || len(all.Events) != 1 || len(all.Characters) != len(res.Characters) {
+		t.Errorf("Expected the characters of the stored event, got %v", all)
+	}
+}
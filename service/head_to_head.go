@@ -0,0 +1,22 @@
+package service
+
+import (
+	"gg/domain"
+	"gg/mapper"
+	"strings"
+)
+
+// GetHeadToHead returns the sets between the player and the opponent, each
+// looked up by start.gg player ID or gamer tag, with the player's record.
+func (t *Tracker) GetHeadToHead(player, opponent string) (*domain.PlayerHeadToHead, error) {
+	player = strings.ToLower(strings.TrimSpace(player))
+	opponent = strings.ToLower(strings.TrimSpace(opponent))
+	if player == "" || opponent == "" {
+		return nil, ErrorPlayerRequired
+	}
+	var history []domain.HeadToHeadSet
+	if stored := t.dbService.GetHeadToHead(domain.HeadToHeadKey(player, opponent)); stored != "" {
+		history = mapper.DBHeadToHeadToHeadToHead(stored)
+	}
+	return domain.NewPlayerHeadToHead(player, opponent, history), nil
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"gg/domain"
+	"testing"
+)
+
+func TestTrackerHeadToHead(t *testing.T) {
+	var exported []*domain.UpsetThread
+	tracker := newTestTracker(&exported)
+	event := trackerEvent
+	event.Paused = true
+	tracker.AddEvent(event)
+	tracker.process(&event)
+
+	upsetThread, err := tracker.GetUpsetThread(slug)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	var played *domain.UpsetThreadItem
+	for _, item := range upsetThread.Winners {
+		if item.HeadToHead != nil {
+			played = &item
+			break
+		}
+	}
+	if played == nil {
+		t.Fatalf("Expected the winners sets to have a head-to-head record, got %+v", upsetThread.Winners)
+	}
+	record, err := tracker.GetHeadToHead(played.WinnersKey(), played.LosersKey())
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	sets := len(record.Sets)
+	if sets == 0 || record.Wins != played.HeadToHead.Wins+1 || record.Sets[sets-1].WinnersName != played.WinnersName {
+		t.Errorf("Expected the set in the head-to-head of %s, got %+v", played.WinnersName, record)
+	}
+
+	tracker.process(&event)
+	upsetThread, _ = tracker.GetUpsetThread(slug)
+	for _, item := range upsetThread.Winners {
+		if item.Id == played.Id && *item.HeadToHead != *played.HeadToHead {
+			t.Errorf("Expected the stored head-to-head to be kept, got %+v", item.HeadToHead)
+		}
+	}
+	if record, _ := tracker.GetHeadToHead(played.LosersKey(), played.WinnersKey()); len(record.Sets) != sets || record.Losses != played.HeadToHead.Wins+1 {
+		t.Errorf("Expected sets to be added once, got %+v", record)
+	}
+	if _, err := tracker.GetHeadToHead("", "sonix"); err != ErrorPlayerRequired {
+		t.Errorf("Expected %s, got %v", ErrorPlayerRequired, err)
+	}
+}
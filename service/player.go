@@ -0,0 +1,29 @@
+package service
+
+import (
+	"errors"
+	"gg/domain"
+	"strings"
+)
+
+var ErrorPlayerRequired = errors.New("player id or gamer tag is required")
+
+// GetPlayerHistory finds the upsets of the player across every event with
+// stored sets, including events that are no longer tracked.
+func (t *Tracker) GetPlayerHistory(player string) (*domain.PlayerHistory, error) {
+	return PlayerHistory(t.storedUpsetThread, t.dbService.GetSetSlugs(), t.eventTitles(), player)
+}
+
+// PlayerHistory finds the upsets of the player in the stored upset threads
+// of the events, as returned by load. Titles are keyed by event slug, and the
+// stored title is used for the others.
+func PlayerHistory(load func(slug, title string) *domain.UpsetThread, slugs []string, titles map[string]string, player string) (*domain.PlayerHistory, error) {
+	if strings.TrimSpace(player) == "" {
+		return nil, ErrorPlayerRequired
+	}
+	var upsetThreads []*domain.UpsetThread
+	for _, slug := range slugs {
+		upsetThreads = append(upsetThreads, load(slug, titles[slug]))
+	}
+	return domain.NewPlayerHistory(player, upsetThreads), nil
+}
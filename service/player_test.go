@@ -0,0 +1,69 @@
+package service
+
+import (
+	"gg/domain"
+	"testing"
+)
+
+func TestTrackerPlayerHistory(t *testing.T) {
+	var exported []*domain.UpsetThread
+	tracker := newTestTracker(&exported)
+	event := trackerEvent
+	event.Paused = true
+	tracker.AddEvent(event)
+	tracker.process(&event)
+	tracker.RemoveEvent(slug)
+
+	if _, err := tracker.GetPlayerHistory(" "); err != ErrorPlayerRequired {
+		t.Errorf("Expected %s, got %v", ErrorPlayerRequired, err)
+	}
+	upsetThread := tracker.service.GetUpsetThreadDB(slug, "")
+	winner := upsetThread.Winners[0]
+	res, err := tracker.GetPlayerHistory(winner.WinnersName)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if len(res.Scored) == 0 || res.Scored[0].Event.Title != trackerEvent.Title || res.GiantKillerScore < winner.UpsetFactor {
+		t.Errorf("Expected the upsets of %s in the untracked event, got %v", winner.WinnersName, res)
+	}
+}
+
+// countingService counts the stored upset threads read through it.
+type countingService struct {
+	ServiceInterface
+	loads int
+}
+
+func (s *countingService) GetUpsetThreadDB(slug, title string) *domain.UpsetThread {
+	s.loads++
+	return s.ServiceInterface.GetUpsetThreadDB(slug, title)
+}
+
+func TestTrackerCachesStoredUpsetThreads(t *testing.T) {
+	var exported []*domain.UpsetThread
+	tracker := newTestTracker(&exported)
+	service := &countingService{ServiceInterface: tracker.service}
+	tracker.service = service
+	event := trackerEvent
+	event.Paused = true
+	tracker.AddEvent(event)
+	tracker.process(&event)
+	loads := service.loads
+
+	for i := 0; i < 3; i++ {
+		tracker.GetPlayerHistory("Sonix")
+		tracker.GetCharacterReport(AllEvents)
+		tracker.GetSeedingReport(slug)
+	}
+	if service.loads != loads {
+		t.Errorf("Expected the reports to read the thread cached by the poll, got %d more reads", service.loads-loads)
+	}
+	tracker.UpdateTitle(slug, "Renamed")
+	if res, _ := tracker.GetSeedingReport(slug); res.Title != "Renamed" {
+		t.Errorf("Expected the tracked title, got %s", res.Title)
+	}
+	tracker.process(&event)
+	if service.loads != loads+1 {
+		t.Errorf("Expected a poll to reload the thread once, got %d reads", service.loads-loads)
+	}
+}
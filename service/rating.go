@@ -0,0 +1,24 @@
+package service
+
+import (
+	"errors"
+	"gg/domain"
+	"gg/mapper"
+	"strings"
+)
+
+var ErrorPlayerNotRated = errors.New("player has no rated sets")
+
+// GetPlayerRating returns the rating of the player, looked up by start.gg
+// player ID or gamer tag, and its history.
+func (t *Tracker) GetPlayerRating(player string) (*domain.PlayerRating, error) {
+	player = strings.ToLower(strings.TrimSpace(player))
+	if player == "" {
+		return nil, ErrorPlayerRequired
+	}
+	rating := t.dbService.GetPlayerRating(player)
+	if rating == "" {
+		return nil, ErrorPlayerNotRated
+	}
+	return mapper.DBPlayerRatingToPlayerRating(rating), nil
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"gg/domain"
+	"testing"
+)
+
+func TestTrackerPlayerRating(t *testing.T) {
+	var exported []*domain.UpsetThread
+	tracker := newTestTracker(&exported)
+	event := trackerEvent
+	event.Paused = true
+	tracker.AddEvent(event)
+	tracker.process(&event)
+
+	upsetThread, err := tracker.GetUpsetThread(slug)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	var rated *domain.UpsetThreadItem
+	for _, item := range upsetThread.Winners {
+		if item.WinProbability > 0 {
+			rated = &item
+			break
+		}
+	}
+	if rated == nil {
+		t.Fatalf("Expected the winners sets to be rated, got %+v", upsetThread.Winners)
+	}
+	rating, err := tracker.GetPlayerRating(rated.WinnersKey())
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	history := len(rating.History)
+	if history == 0 || rating.Name != rated.WinnersName {
+		t.Errorf("Expected the rating history of %s, got %+v", rated.WinnersName, rating)
+	}
+
+	tracker.process(&event)
+	upsetThread, _ = tracker.GetUpsetThread(slug)
+	for _, item := range upsetThread.Winners {
+		if item.Id == rated.Id && item.WinProbability != rated.WinProbability {
+			t.Errorf("Expected the stored win probability to be kept, got %v", item.WinProbability)
+		}
+	}
+	if rating, _ := tracker.GetPlayerRating(rated.WinnersKey()); len(rating.History) != history {
+		t.Errorf("Expected sets to be rated once, got %d changes instead of %d", len(rating.History), history)
+	}
+	if _, err := tracker.GetPlayerRating("nobody"); err != ErrorPlayerNotRated {
+		t.Errorf("Expected %s, got %v", ErrorPlayerNotRated, err)
+	}
+}
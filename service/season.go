@@ -0,0 +1,143 @@
+package service
+
+import (
+	"errors"
+	"gg/domain"
+	"gg/mapper"
+	"slices"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrorSeasonNotFound      = errors.New("season not found")
+	ErrorSeasonAlreadyExists = errors.New("season already exists")
+	ErrorSeasonNameRequired  = errors.New("season name is required")
+	ErrorInvalidSeasonEvent  = errors.New("season events must be event slugs such as tournament/<tournament>/event/<event>")
+)
+
+// normaliseSeason trims the event slugs of the season and drops duplicates.
+func normaliseSeason(season domain.Season) (domain.Season, error) {
+	if strings.TrimSpace(season.Name) == "" {
+		return season, ErrorSeasonNameRequired
+	}
+	var events []string
+	for _, slug := range season.Events {
+		slug = strings.TrimSpace(slug)
+		if !strings.HasPrefix(slug, "tournament/") || !strings.Contains(slug, "/event/") {
+			return season, ErrorInvalidSeasonEvent
+		}
+		if !slices.Contains(events, slug) {
+			events = append(events, slug)
+		}
+	}
+	season.Events = events
+	return season, nil
+}
+
+func (t *Tracker) GetSeasons() []domain.Season {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	seasons := make([]domain.Season, 0, len(t.seasons))
+	for _, season := range t.seasons {
+		seasons = append(seasons, *season)
+	}
+	sort.Slice(seasons, func(i, j int) bool {
+		return seasons[i].Id < seasons[j].Id
+	})
+	return seasons
+}
+
+func (t *Tracker) GetSeason(id string) (*domain.Season, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	season, ok := t.seasons[id]
+	if !ok {
+		return nil, ErrorSeasonNotFound
+	}
+	res := *season
+	return &res, nil
+}
+
+// AddSeason stores a new season. Its ID is derived from the name when
+// missing.
+func (t *Tracker) AddSeason(season domain.Season) (*domain.Season, error) {
+	season, err := normaliseSeason(season)
+	if err != nil {
+		return nil, err
+	}
+	if season.Id == "" {
+		season.Id = idFromName(season.Name)
+	}
+	if season.Id == "" {
+		return nil, ErrorSeasonNameRequired
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.seasons[season.Id]; ok {
+		return nil, ErrorSeasonAlreadyExists
+	}
+	t.seasons[season.Id] = &season
+	t.dbService.AddSeason(season.Id, mapper.SeasonToDBSeason(season))
+	res := season
+	return &res, nil
+}
+
+// UpdateSeason replaces the name and events of the season with the same ID.
+func (t *Tracker) UpdateSeason(season domain.Season) error {
+	season, err := normaliseSeason(season)
+	if err != nil {
+		return err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	stored, ok := t.seasons[season.Id]
+	if !ok {
+		return ErrorSeasonNotFound
+	}
+	stored.Name = season.Name
+	stored.Events = season.Events
+	t.dbService.AddSeason(stored.Id, mapper.SeasonToDBSeason(*stored))
+	return nil
+}
+
+func (t *Tracker) RemoveSeason(id string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.seasons[id]; !ok {
+		return ErrorSeasonNotFound
+	}
+	delete(t.seasons, id)
+	t.dbService.RemoveSeason(id)
+	return nil
+}
+
+// GetSeasonLeaderboard ranks the stored upsets of the season's events,
+// including events that are no longer tracked.
+func (t *Tracker) GetSeasonLeaderboard(id string) (*domain.SeasonLeaderboard, error) {
+	season, err := t.GetSeason(id)
+	if err != nil {
+		return nil, err
+	}
+	return SeasonLeaderboard(t.storedUpsetThread, *season, t.eventTitles()), nil
+}
+
+// eventTitles returns the titles of the tracked events by slug.
+func (t *Tracker) eventTitles() map[string]string {
+	titles := make(map[string]string)
+	for _, event := range t.GetEvents() {
+		titles[event.Slug] = event.Title
+	}
+	return titles
+}
+
+// SeasonLeaderboard ranks the stored upsets of the season's events, as
+// returned by load. Titles are keyed by event slug, and the stored title is
+// used for the others.
+func SeasonLeaderboard(load func(slug, title string) *domain.UpsetThread, season domain.Season, titles map[string]string) *domain.SeasonLeaderboard {
+	var upsetThreads []*domain.UpsetThread
+	for _, slug := range season.Events {
+		upsetThreads = append(upsetThreads, load(slug, titles[slug]))
+	}
+	return domain.NewSeasonLeaderboard(season, upsetThreads, domain.LeaderboardSize)
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"gg/domain"
+	"testing"
+)
+
+func TestTrackerSeasons(t *testing.T) {
+	var exported []*domain.UpsetThread
+	tracker := newTestTracker(&exported)
+
+	season, err := tracker.AddSeason(domain.Season{Name: "Circuit 2024", Events: []string{slug, " " + slug + " "}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if season.Id != "circuit-2024" || len(season.Events) != 1 {
+		t.Errorf("Expected id circuit-2024 with one event, got %v", season)
+	}
+	if _, err := tracker.AddSeason(domain.Season{Name: "Circuit 2024"}); err != ErrorSeasonAlreadyExists {
+		t.Errorf("Expected %s, got %v", ErrorSeasonAlreadyExists, err)
+	}
+	if _, err := tracker.AddSeason(domain.Season{Name: "Other", Events: []string{"genesis"}}); err != ErrorInvalidSeasonEvent {
+		t.Errorf("Expected %s, got %v", ErrorInvalidSeasonEvent, err)
+	}
+	if err := tracker.UpdateSeason(domain.Season{Id: "unknown", Name: "Unknown"}); err != ErrorSeasonNotFound {
+		t.Errorf("Expected %s, got %v", ErrorSeasonNotFound, err)
+	}
+
+	event := trackerEvent
+	event.Paused = true
+	tracker.AddEvent(event)
+	tracker.process(&event)
+	tracker.RemoveEvent(slug)
+	leaderboard, err := tracker.GetSeasonLeaderboard("circuit-2024")
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if len(leaderboard.Events) != 1 || leaderboard.Events[0].Title != trackerEvent.Title {
+		t.Errorf("Expected the stored title of the untracked event, got %v", leaderboard.Events)
+	}
+	if len(leaderboard.BiggestUpsets) == 0 || len(leaderboard.MostUpsets) == 0 {
+		t.Errorf("Expected upsets of the stored event, got %v", leaderboard)
+	}
+
+	if err := tracker.RemoveSeason("circuit-2024"); err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if len(*tracker.dbService.GetSeasons()) != 0 {
+		t.Errorf("Expected no stored seasons")
+	}
+}
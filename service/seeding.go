@@ -0,0 +1,29 @@
+package service
+
+import (
+	"gg/domain"
+	"slices"
+)
+
+// GetSeedingReport measures the seeding of the event, tracked or not, and
+// benchmarks it against every other event with stored sets.
+func (t *Tracker) GetSeedingReport(slug string) (*domain.SeedingReport, error) {
+	return SeedingReport(t.storedUpsetThread, t.dbService.GetSetSlugs(), t.eventTitles(), slug)
+}
+
+// SeedingReport measures the seeding of the event from its stored upset
+// thread, as returned by load, using the other events of slugs as
+// benchmarks. Titles are keyed by event slug, and the stored title is used
+// for the others.
+func SeedingReport(load func(slug, title string) *domain.UpsetThread, slugs []string, titles map[string]string, slug string) (*domain.SeedingReport, error) {
+	if !slices.Contains(slugs, slug) {
+		return nil, ErrorEventNotFound
+	}
+	var others []*domain.UpsetThread
+	for _, other := range slugs {
+		if other != slug {
+			others = append(others, load(other, titles[other]))
+		}
+	}
+	return domain.NewSeedingReport(load(slug, titles[slug]), others), nil
+}
@@ -0,0 +1,27 @@
+package service
+
+import (
+	"gg/domain"
+	"testing"
+)
+
+func TestTrackerSeedingReport(t *testing.T) {
+	var exported []*domain.UpsetThread
+	tracker := newTestTracker(&exported)
+	event := trackerEvent
+	event.Paused = true
+	tracker.AddEvent(event)
+	tracker.process(&event)
+
+	if _, err := tracker.GetSeedingReport("tournament/unknown/event/singles"); err != ErrorEventNotFound {
+		t.Errorf("Expected %s, got %v", ErrorEventNotFound, err)
+	}
+	tracker.RemoveEvent(slug)
+	res, err := tracker.GetSeedingReport(slug)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if res.Title != trackerEvent.Title || res.Metrics.Entrants == 0 || res.Benchmark.Events != 0 {
+		t.Errorf("Expected the seeding of the stored event, got %+v", res)
+	}
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"cmp"
 	"encoding/json"
+	"fmt"
 	"gg/client/startgg"
 	"gg/db"
 	"gg/domain"
@@ -13,22 +14,26 @@ import (
 	"slices"
 	"sort"
 	"strconv"
+	"sync"
 	"time"
 )
 
+// Time to wait before requesting each page of sets from start.gg.
+const PAGE_DELAY = 800 * time.Millisecond
+
 type FileInterface interface {
-	ReadFile(fileName string) []byte
+	ReadFile(fileName string) ([]byte, error)
 	WriteString(fileName, data string)
 }
 
 type FileReaderWriter struct{}
 
-func (f *FileReaderWriter) ReadFile(fileName string) []byte {
+func (f *FileReaderWriter) ReadFile(fileName string) ([]byte, error) {
 	file, err := os.ReadFile(fileName)
 	if err != nil {
-		log.Fatalf("Error while reading file. e=%s\n", err)
+		return nil, fmt.Errorf("error while reading file: %w", err)
 	}
-	return file
+	return file, nil
 }
 
 func (f *FileReaderWriter) WriteString(fileName, data string) {
@@ -46,36 +51,67 @@ func (f *FileReaderWriter) WriteString(fileName, data string) {
 
 type ServiceInterface interface {
 	toDomainSet(node startgg.Node, slug string) domain.Set
-	getSetsFromAPI(slug string) *[]domain.Set
+	getSetsFromAPI(slug string) (*[]domain.Set, error)
 	getUpsetThread(sets []domain.Set) *domain.UpsetThread
 	submitToSubreddit()
 	addSets(slug string, upsetThread *domain.UpsetThread)
 	GetUpsetThreadDB(slug, title string) *domain.UpsetThread
-	Process(slug, title, subreddit, file, gameSlug string) *domain.UpsetThread
+	GetSampleUpsetThread() (*domain.UpsetThread, error)
+	GetBracket(slug string) (*domain.Bracket, error)
+	Process(slug, title, subreddit, file, gameSlug string) (*domain.UpsetThread, error)
 }
 
 type Service struct {
 	dbService     db.DBServiceInterface
 	startGGClient startgg.ClientInterface
 	file          FileInterface
+	pageDelay     time.Duration
+	rulesets      domain.Rulesets
+	// historyMu serialises updates to ratings and head-to-head records, as
+	// events are polled concurrently and players enter several of them.
+	historyMu sync.Mutex
 }
 
 func toDomainEntrant(entrant startgg.Entrant) domain.Entrant {
+	var playerIds []int
+	for _, participant := range entrant.Participants {
+		playerIds = append(playerIds, participant.Player.Id)
+	}
+	prefix, tag := domain.SplitName(entrant.Name)
+	if len(entrant.Participants) == 1 && entrant.Participants[0].GamerTag != "" {
+		prefix = domain.NormaliseName(entrant.Participants[0].Prefix)
+		tag = domain.NormaliseName(entrant.Participants[0].GamerTag)
+	} else if len(entrant.Participants) > 1 {
+		prefix, tag = "", domain.NormaliseName(entrant.Name)
+	}
 	return domain.Entrant{
 		Id:          entrant.Id,
 		Name:        entrant.Name,
 		InitialSeed: entrant.InitialSeedNum,
 		Placement:   entrant.Standing.Placement,
 		IsFinal:     entrant.Standing.IsFinal,
+		PlayerIds:   playerIds,
+		Prefix:      prefix,
+		Tag:         tag,
 	}
 }
 
-func (s *Service) getCharacterName(key int, slug string) string {
-	if !s.dbService.IsCharactersLoaded(slug) {
-		res := s.startGGClient.GetCharacters(slug)
-		s.dbService.AddCharacters(res.Data.VideoGame.Characters, slug)
-		s.dbService.SetIsCharactersLoaded(slug)
+// loadCharacters stores the names of the game's characters the first time
+// sets of the game are mapped.
+func (s *Service) loadCharacters(slug string) error {
+	if slug == "" || s.dbService.IsCharactersLoaded(slug) {
+		return nil
+	}
+	res, err := s.startGGClient.GetCharacters(slug)
+	if err != nil {
+		return fmt.Errorf("something went wrong getting characters: %w", err)
 	}
+	s.dbService.AddCharacters(res.Data.VideoGame.Characters, slug)
+	s.dbService.SetIsCharactersLoaded(slug)
+	return nil
+}
+
+func (s *Service) getCharacterName(key int, slug string) string {
 	return s.dbService.GetCharacterName(key, slug)
 }
 
@@ -96,24 +132,44 @@ func (s *Service) toDomainSelection(selection startgg.Selection, slug string) do
 	}
 }
 
-func (s *Service) toDomainGame(game startgg.Game, slug string) domain.Game {
+// toDomainGame maps a game of the set. The entrants are in slot order, which
+// is the order of the game's entrant scores.
+func (s *Service) toDomainGame(game startgg.Game, entrants []domain.Entrant, slug string) domain.Game {
 	var selections []domain.Selection
 	if game.Selections != nil {
 		for _, selection := range game.Selections {
 			selections = append(selections, s.toDomainSelection(selection, slug))
 		}
 	}
+	var scores map[int]int
+	if game.Entrant1Score != nil && game.Entrant2Score != nil && len(entrants) == 2 {
+		scores = map[int]int{
+			entrants[0].Id: *game.Entrant1Score,
+			entrants[1].Id: *game.Entrant2Score,
+		}
+	}
 	return domain.Game{
 		Id:         game.Id,
 		WinnerId:   game.WinnerId,
 		Selections: selections,
+		Scores:     scores,
 	}
 }
 
 func (s *Service) toDomainSet(node startgg.Node, slug string) domain.Set {
 	entrants := make([]domain.Entrant, 0)
 	for _, slot := range node.Slots {
-		entrants = append(entrants, toDomainEntrant(slot.Entrant))
+		entrant := toDomainEntrant(slot.Entrant)
+		if slot.Standing != nil {
+			if value := slot.Standing.Stats.Score.Value; value != nil {
+				score := int(*value)
+				entrant.Score = &score
+			}
+			if label := slot.Standing.Stats.Score.DisplayValue; label == "W" || label == "L" {
+				entrant.ScoreLabel = label
+			}
+		}
+		entrants = append(entrants, entrant)
 	}
 	var games []domain.Game
 	lPlacement := 0
@@ -123,11 +179,15 @@ func (s *Service) toDomainSet(node startgg.Node, slug string) domain.Set {
 		}
 	}
 	if node.Games != nil {
-		for _, game := range node.Games {
-			games = append(games, s.toDomainGame(game, slug))
+		nodeGames := slices.Clone(node.Games)
+		slices.SortStableFunc(nodeGames, func(i, j startgg.Game) int {
+			return cmp.Compare(i.OrderNum, j.OrderNum)
+		})
+		for _, game := range nodeGames {
+			games = append(games, s.toDomainGame(game, entrants, slug))
 		}
 	}
-	return *domain.NewSet(
+	set := domain.NewSet(
 		strconv.Itoa(node.Id),
 		node.DisplayScore,
 		&node.FullRoundText,
@@ -139,35 +199,108 @@ func (s *Service) toDomainSet(node startgg.Node, slug string) domain.Set {
 		&games,
 		node.CompletedAt,
 	)
+	set.GamesType = node.SetGamesType
+	set.PhaseGroupId = node.PhaseGroup.Id
+	set.BracketType = domain.BracketType(node.PhaseGroup.BracketType)
+	set.PhaseId = node.PhaseGroup.Phase.Id
+	set.PhaseName = node.PhaseGroup.Phase.Name
+	// A phase played as a single bracket, such as Top 64, has no pools to
+	// tell apart.
+	if node.PhaseGroup.Phase.GroupCount > 1 {
+		set.PoolIdentifier = node.PhaseGroup.DisplayIdentifier
+	}
+	set.GameSlug = slug
+	set.VodUrl = node.VodUrl
+	if node.Stream != nil {
+		set.StreamName = node.Stream.StreamName
+		set.StreamSource = node.Stream.StreamSource
+	}
+	return *set
 }
 
-func (s *Service) getSetsFromAPI(slug string) *[]domain.Set {
+func (s *Service) getSetsFromAPI(slug string) (*[]domain.Set, error) {
 	page := 1
 	var sets []domain.Set
+	finalPhaseId, finalPhaseOrder := 0, 0
 	for {
-		time.Sleep(800 * time.Millisecond)
+		time.Sleep(s.pageDelay)
 		res, err := s.startGGClient.GetEvent(slug, page)
 		if err == startgg.ErrorGreaterthan10KEntry {
 			log.Println("Finishing because cannot query more than 10,000th entry.")
 			break
 		}
 		if err != nil {
-			log.Fatalf("Something went wrong getting event. e=%s\n", err)
+			return nil, fmt.Errorf("something went wrong getting event: %w", err)
 		}
 		if res.Errors != nil {
-			log.Fatalf("Response contains errors. e=%s\n", res.Errors)
+			return nil, fmt.Errorf("response contains errors: %v", res.Errors)
 		}
 		totalPages := res.Data.Event.Sets.PageInfo.TotalPages
 		log.Printf("Event received. slug=%s page=%v totalPage=%v\n", slug, page, totalPages)
+		if timezone := res.Data.Event.Tournament.Timezone; page == 1 && timezone != "" {
+			s.dbService.SetEventInfo(slug, "timezone", timezone)
+		}
+		for _, phase := range res.Data.Event.Phases {
+			if finalPhaseId == 0 || phase.PhaseOrder > finalPhaseOrder {
+				finalPhaseId, finalPhaseOrder = phase.Id, phase.PhaseOrder
+			}
+		}
 		if page > totalPages {
 			break
 		}
 		page++
+		if err := s.loadCharacters(res.Data.Event.Videogame.Slug); err != nil {
+			return nil, err
+		}
 		for _, node := range res.Data.Event.Sets.Nodes {
 			sets = append(sets, s.toDomainSet(node, res.Data.Event.Videogame.Slug))
 		}
 	}
-	return &sets
+	domain.MarkFinalPhase(sets, finalPhaseId)
+	return &sets, nil
+}
+
+// toBracketSet maps a set of the bracket. A slot is filled by the set feeding
+// it rather than its entrant, so that simulations play out the whole event.
+func toBracketSet(node startgg.BracketNode, entrants map[int]domain.Entrant) domain.BracketSet {
+	res := domain.BracketSet{Id: string(node.Id)}
+	for _, slot := range node.Slots {
+		var bracketSlot domain.BracketSlot
+		if slot.PrereqType == "set" && slot.PrereqId != "" {
+			bracketSlot.PrereqSetId, bracketSlot.PrereqPlacement = slot.PrereqId, slot.PrereqPlacement
+		} else if slot.Entrant != nil && slot.Entrant.Id != 0 {
+			entrant := toDomainEntrant(*slot.Entrant)
+			entrants[entrant.Id] = entrant
+			bracketSlot.EntrantId = entrant.Id
+		} else if slot.PrereqType == "bye" {
+			bracketSlot.Bye = true
+		}
+		res.Slots = append(res.Slots, bracketSlot)
+	}
+	return res
+}
+
+// GetBracket fetches every set of the event from start.gg, played or not.
+func (s *Service) GetBracket(slug string) (*domain.Bracket, error) {
+	res := &domain.Bracket{Entrants: make(map[int]domain.Entrant)}
+	for page := 1; ; page++ {
+		time.Sleep(s.pageDelay)
+		bracket, err := s.startGGClient.GetBracket(slug, page)
+		if err == startgg.ErrorGreaterthan10KEntry {
+			log.Println("Finishing because cannot query more than 10,000th entry.")
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("something went wrong getting bracket: %w", err)
+		}
+		for _, node := range bracket.Data.Event.Sets.Nodes {
+			res.Sets = append(res.Sets, toBracketSet(node, res.Entrants))
+		}
+		if page >= bracket.Data.Event.Sets.PageInfo.TotalPages {
+			break
+		}
+	}
+	return res, nil
 }
 
 func applyFilter(upsetFactor, winnerInitialSeed, loserInitialSeed int, isDQ bool, score *string, minUpsetFactor, maxSeed int, includeDQ bool) bool {
@@ -178,9 +311,25 @@ func applyFilter(upsetFactor, winnerInitialSeed, loserInitialSeed int, isDQ bool
 }
 
 func (s *Service) getUpsetThread(sets []domain.Set) *domain.UpsetThread {
-	var winners, losers, notables, dqs, other []domain.Set
+	domain.MarkGrandFinals(sets)
+	domain.MarkPoolRecords(sets)
+	var grandFinals, winners, losers, pools, notables, dqs, other []domain.Set
 	for _, set := range sets {
-		if set.IsWinnersBracket() && applyFilter(
+		set.Notable = set.IsNotable(s.rulesets.For(set.GameSlug))
+		if set.GrandFinal != "" {
+			grandFinals = append(grandFinals, set)
+		} else if set.IsPoolStage() && applyFilter(
+			set.UpsetFactor,
+			set.Winner.InitialSeed,
+			set.Loser.InitialSeed,
+			set.IsDQ(),
+			set.Score,
+			1,
+			50,
+			false,
+		) {
+			pools = append(pools, set)
+		} else if set.IsWinnersBracket() && applyFilter(
 			set.UpsetFactor,
 			set.Winner.InitialSeed,
 			set.Loser.InitialSeed,
@@ -191,7 +340,7 @@ func (s *Service) getUpsetThread(sets []domain.Set) *domain.UpsetThread {
 			false,
 		) {
 			winners = append(winners, set)
-		} else if !set.IsWinnersBracket() && applyFilter(
+		} else if set.IsLosersBracket() && applyFilter(
 			set.UpsetFactor,
 			set.Winner.InitialSeed,
 			set.Loser.InitialSeed,
@@ -213,7 +362,7 @@ func (s *Service) getUpsetThread(sets []domain.Set) *domain.UpsetThread {
 			true,
 		) {
 			dqs = append(dqs, set)
-		} else if set.IsNotable() && applyFilter(
+		} else if set.Notable && applyFilter(
 			-set.UpsetFactor,
 			set.Winner.InitialSeed,
 			set.Loser.InitialSeed,
@@ -231,13 +380,19 @@ func (s *Service) getUpsetThread(sets []domain.Set) *domain.UpsetThread {
 	sort.Slice(notables, func(i, j int) bool {
 		return notables[i].UpsetFactor < notables[j].UpsetFactor
 	})
-	var winnersUpsetThreadItems, losersUpsetThreadItems, notablesUpsetThreadItems, dqsUpsetThreadItems, otherUpsetThreadItems []domain.UpsetThreadItem
+	var grandFinalsUpsetThreadItems, winnersUpsetThreadItems, losersUpsetThreadItems, poolsUpsetThreadItems, notablesUpsetThreadItems, dqsUpsetThreadItems, otherUpsetThreadItems []domain.UpsetThreadItem
+	for _, set := range grandFinals {
+		grandFinalsUpsetThreadItems = append(grandFinalsUpsetThreadItems, mapper.SetToUpsetThreadItem(set, "grandfinals"))
+	}
 	for _, set := range winners {
 		winnersUpsetThreadItems = append(winnersUpsetThreadItems, mapper.SetToUpsetThreadItem(set, "winners"))
 	}
 	for _, set := range losers {
 		losersUpsetThreadItems = append(losersUpsetThreadItems, mapper.SetToUpsetThreadItem(set, "losers"))
 	}
+	for _, set := range pools {
+		poolsUpsetThreadItems = append(poolsUpsetThreadItems, mapper.SetToUpsetThreadItem(set, "pools"))
+	}
 	for _, set := range notables {
 		notablesUpsetThreadItems = append(notablesUpsetThreadItems, mapper.SetToUpsetThreadItem(set, "notables"))
 	}
@@ -248,11 +403,13 @@ func (s *Service) getUpsetThread(sets []domain.Set) *domain.UpsetThread {
 		otherUpsetThreadItems = append(otherUpsetThreadItems, mapper.SetToUpsetThreadItem(set, "other"))
 	}
 	return &domain.UpsetThread{
-		Winners:  winnersUpsetThreadItems,
-		Losers:   losersUpsetThreadItems,
-		Notables: notablesUpsetThreadItems,
-		DQs:      dqsUpsetThreadItems,
-		Other:    otherUpsetThreadItems,
+		GrandFinals: grandFinalsUpsetThreadItems,
+		Winners:     winnersUpsetThreadItems,
+		Losers:      losersUpsetThreadItems,
+		Pools:       poolsUpsetThreadItems,
+		Notables:    notablesUpsetThreadItems,
+		DQs:         dqsUpsetThreadItems,
+		Other:       otherUpsetThreadItems,
 	}
 }
 
@@ -272,14 +429,21 @@ func notablesSort(entry []domain.UpsetThreadItem, i, j domain.UpsetThreadItem) i
 
 func (s *Service) GetUpsetThreadDB(slug, title string) *domain.UpsetThread {
 	setMapping := s.dbService.GetSets(slug)
-	var winners, losers, notables, dqs, other []domain.UpsetThreadItem
+	if title == "" {
+		title = s.dbService.GetEventInfo(slug, "title")
+	}
+	var grandFinals, winners, losers, pools, notables, dqs, other []domain.UpsetThreadItem
 	for setId, set := range *setMapping {
 		upsetThreadItem := mapper.DBSetToUpsetThreadItem(setId, set)
 		category := upsetThreadItem.Category
-		if category == "winners" {
+		if category == "grandfinals" {
+			grandFinals = append(grandFinals, *upsetThreadItem)
+		} else if category == "winners" {
 			winners = append(winners, *upsetThreadItem)
 		} else if category == "losers" {
 			losers = append(losers, *upsetThreadItem)
+		} else if category == "pools" {
+			pools = append(pools, *upsetThreadItem)
 		} else if category == "notables" {
 			notables = append(notables, *upsetThreadItem)
 		} else if category == "dqs" {
@@ -288,12 +452,22 @@ func (s *Service) GetUpsetThreadDB(slug, title string) *domain.UpsetThread {
 			other = append(other, *upsetThreadItem)
 		}
 	}
+	// The grand final comes before the reset.
+	slices.SortFunc(grandFinals, func(i, j domain.UpsetThreadItem) int {
+		return cmp.Or(
+			cmp.Compare(i.CompletedAt, j.CompletedAt),
+			cmp.Compare(i.Id, j.Id),
+		)
+	})
 	slices.SortFunc(winners, func(i, j domain.UpsetThreadItem) int {
 		return defaultSort(winners, i, j)
 	})
 	slices.SortFunc(losers, func(i, j domain.UpsetThreadItem) int {
 		return defaultSort(losers, i, j)
 	})
+	slices.SortFunc(pools, func(i, j domain.UpsetThreadItem) int {
+		return defaultSort(pools, i, j)
+	})
 	slices.SortFunc(notables, func(i, j domain.UpsetThreadItem) int {
 		return notablesSort(notables, i, j)
 	})
@@ -304,13 +478,16 @@ func (s *Service) GetUpsetThreadDB(slug, title string) *domain.UpsetThread {
 		return defaultSort(other, i, j)
 	})
 	return &domain.UpsetThread{
-		Slug:     slug,
-		Title:    title,
-		Winners:  winners,
-		Losers:   losers,
-		Notables: notables,
-		DQs:      dqs,
-		Other:    other,
+		Slug:        slug,
+		Title:       title,
+		Timezone:    s.dbService.GetEventInfo(slug, "timezone"),
+		GrandFinals: grandFinals,
+		Winners:     winners,
+		Losers:      losers,
+		Pools:       pools,
+		Notables:    notables,
+		DQs:         dqs,
+		Other:       other,
 	}
 }
 
@@ -320,12 +497,18 @@ func (s *Service) submitToSubreddit() {
 
 func (s *Service) addSets(slug string, upsetThread *domain.UpsetThread) {
 	setMapping := make(map[string]string, 0)
+	for _, s := range upsetThread.GrandFinals {
+		setMapping[s.Id] = mapper.UpsetThreadItemToDBSet(s)
+	}
 	for _, s := range upsetThread.Winners {
 		setMapping[s.Id] = mapper.UpsetThreadItemToDBSet(s)
 	}
 	for _, s := range upsetThread.Losers {
 		setMapping[s.Id] = mapper.UpsetThreadItemToDBSet(s)
 	}
+	for _, s := range upsetThread.Pools {
+		setMapping[s.Id] = mapper.UpsetThreadItemToDBSet(s)
+	}
 	for _, s := range upsetThread.Notables {
 		setMapping[s.Id] = mapper.UpsetThreadItemToDBSet(s)
 	}
@@ -338,35 +521,159 @@ func (s *Service) addSets(slug string, upsetThread *domain.UpsetThread) {
 	s.dbService.AddSets(slug, &setMapping)
 }
 
-func (s *Service) Process(slug, title, subreddit, file, gameSlug string) *domain.UpsetThread {
+// annotate goes through the sets completed since the last poll, in the order
+// they were completed. It updates the ratings of their players, setting the
+// winners' pre-match win probability, and adds them to the players'
+// head-to-head history, setting the winners' record against the losers before
+// the set. Sets annotated by an earlier poll keep their stored annotations.
+func (s *Service) annotate(slug string, upsetThread *domain.UpsetThread) {
+	s.historyMu.Lock()
+	defer s.historyMu.Unlock()
+	stored := make(map[string]*domain.UpsetThreadItem)
+	for setId, set := range *s.dbService.GetSets(slug) {
+		stored[setId] = mapper.DBSetToUpsetThreadItem(setId, set)
+	}
+	var items []*domain.UpsetThreadItem
+	for _, section := range [][]domain.UpsetThreadItem{upsetThread.GrandFinals, upsetThread.Winners, upsetThread.Losers, upsetThread.Pools, upsetThread.Notables, upsetThread.DQs, upsetThread.Other} {
+		for i := range section {
+			items = append(items, &section[i])
+		}
+	}
+	slices.SortFunc(items, func(i, j *domain.UpsetThreadItem) int {
+		return cmp.Or(
+			cmp.Compare(i.CompletedAt, j.CompletedAt),
+			cmp.Compare(i.Id, j.Id),
+		)
+	})
+	getRating := func(player string) *domain.PlayerRating {
+		if rating := s.dbService.GetPlayerRating(player); rating != "" {
+			return mapper.DBPlayerRatingToPlayerRating(rating)
+		}
+		return domain.NewPlayerRating(player)
+	}
+	for _, item := range items {
+		if previous, ok := stored[item.Id]; ok {
+			item.WinProbability, item.HeadToHead = previous.WinProbability, previous.HeadToHead
+		}
+		if !domain.IsRateable(*item) {
+			continue
+		}
+		if item.WinProbability == 0 && s.dbService.AddRatedSet(slug+"/"+item.Id) {
+			winner, loser := getRating(item.WinnersKey()), getRating(item.LosersKey())
+			item.WinProbability = domain.RateSet(winner, loser, slug, *item)
+			s.dbService.SetPlayerRating(winner.Player, mapper.PlayerRatingToDBPlayerRating(*winner))
+			s.dbService.SetPlayerRating(loser.Player, mapper.PlayerRatingToDBPlayerRating(*loser))
+		}
+		if item.HeadToHead == nil {
+			s.addHeadToHead(slug, item)
+		}
+	}
+}
+
+// addHeadToHead sets the winner's record against the loser from the sets
+// between them stored so far and adds the set to their history. Ties have no
+// winner to keep a record for.
+func (s *Service) addHeadToHead(slug string, item *domain.UpsetThreadItem) {
+	if item.Outcome == domain.OutcomeTie {
+		return
+	}
+	players := domain.HeadToHeadKey(item.WinnersKey(), item.LosersKey())
+	var history []domain.HeadToHeadSet
+	if stored := s.dbService.GetHeadToHead(players); stored != "" {
+		history = mapper.DBHeadToHeadToHeadToHead(stored)
+	}
+	item.HeadToHead = domain.NewHeadToHead(history, slug, *item)
+	if history, added := domain.AddHeadToHeadSet(history, slug, *item); added {
+		s.dbService.SetHeadToHead(players, mapper.HeadToHeadToDBHeadToHead(history))
+	}
+}
+
+func (s *Service) getSetsFromNodes(data []byte, gameSlug string) ([]domain.Set, error) {
+	var nodes []startgg.Node
+	if err := json.Unmarshal(data, &nodes); err != nil {
+		return nil, fmt.Errorf("error while unmarshaling node: %w", err)
+	}
+	if err := s.loadCharacters(gameSlug); err != nil {
+		return nil, err
+	}
+	var sets []domain.Set
+	for _, node := range nodes {
+		sets = append(sets, s.toDomainSet(node, gameSlug))
+	}
+	domain.MarkFinalPhase(sets, 0)
+	return sets, nil
+}
+
+// SampleGameSlug stores the characters of the sample sets apart from real
+// games.
+const SampleGameSlug = "sample"
+
+// GetSampleUpsetThread builds an upset thread from db.SampleSets without
+// storing the sets or calling start.gg, e.g. to preview an output.
+func (s *Service) GetSampleUpsetThread() (*domain.UpsetThread, error) {
+	if !s.dbService.IsCharactersLoaded(SampleGameSlug) {
+		var charactersResponse startgg.CharactersResponse
+		if err := json.Unmarshal(db.SampleCharacters, &charactersResponse); err != nil {
+			return nil, fmt.Errorf("error while unmarshaling characters: %w", err)
+		}
+		s.dbService.AddCharacters(charactersResponse.Data.VideoGame.Characters, SampleGameSlug)
+		s.dbService.SetIsCharactersLoaded(SampleGameSlug)
+	}
+	sets, err := s.getSetsFromNodes(db.SampleSets, SampleGameSlug)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(sets, func(i, j int) bool {
+		return sets[i].UpsetFactor > sets[j].UpsetFactor
+	})
+	upsetThread := s.getUpsetThread(sets)
+	upsetThread.Title = "Sample"
+	upsetThread.Slug = "tournament/sample/event/sample"
+	return upsetThread, nil
+}
+
+func (s *Service) Process(slug, title, subreddit, file, gameSlug string) (*domain.UpsetThread, error) {
 	var sets []domain.Set
 	if file != "" {
 		log.Println("Using file data", file)
-		storedFile := s.file.ReadFile(file)
-		var nodes []startgg.Node
-		if err := json.Unmarshal(storedFile, &nodes); err != nil {
-			log.Fatalf("Error while unmarshaling node. e=%s\n", err)
+		data, err := s.file.ReadFile(file)
+		if err != nil {
+			return nil, err
 		}
-		for _, node := range nodes {
-			sets = append(sets, s.toDomainSet(node, gameSlug))
+		setsFromFile, err := s.getSetsFromNodes(data, gameSlug)
+		if err != nil {
+			return nil, err
 		}
+		sets = setsFromFile
 	} else {
 		log.Println("Fetching data from startgg")
-		sets = *s.getSetsFromAPI(slug)
+		setsFromAPI, err := s.getSetsFromAPI(slug)
+		if err != nil {
+			return nil, err
+		}
+		sets = *setsFromAPI
 	}
 	sort.Slice(sets, func(i, j int) bool {
 		return sets[i].UpsetFactor > sets[j].UpsetFactor
 	})
 	upsetThread := s.getUpsetThread(sets)
+	s.annotate(slug, upsetThread)
 	s.addSets(slug, upsetThread)
+	// The title is kept for seasons, which can name events that are no longer
+	// tracked.
+	if title != "" {
+		s.dbService.SetEventInfo(slug, "title", title)
+	}
 	savedUpsetThread := s.GetUpsetThreadDB(slug, title)
-	return savedUpsetThread
+	return savedUpsetThread, nil
 }
 
-func NewService(dbService db.DBServiceInterface, startGGClient startgg.ClientInterface, file FileInterface) *Service {
+func NewService(dbService db.DBServiceInterface, startGGClient startgg.ClientInterface, file FileInterface, pageDelay time.Duration, rulesets domain.Rulesets) *Service {
 	return &Service{
 		dbService:     dbService,
 		startGGClient: startGGClient,
 		file:          file,
+		pageDelay:     pageDelay,
+		rulesets:      rulesets,
 	}
 }
@@ -16,7 +16,7 @@ import (
 
 type FakeStartGGClient struct{}
 
-func (f *FakeStartGGClient) GetCharacters(slug string) startgg.CharactersResponse {
+func (f *FakeStartGGClient) GetCharacters(slug string) (*startgg.CharactersResponse, error) {
 	data, err := os.ReadFile("../db/characters.json")
 	if err != nil {
 		log.Fatalf("Error while reading file. e=%s\n", err)
@@ -25,21 +25,36 @@ func (f *FakeStartGGClient) GetCharacters(slug string) startgg.CharactersRespons
 	if err := json.Unmarshal(data, &charactersResponse); err != nil {
 		log.Fatalf("Error while unmarshaling characters. e=%s\n", err)
 	}
-	return charactersResponse
+	return &charactersResponse, nil
 }
 
 func (f *FakeStartGGClient) GetEvent(slug string, page int) (*startgg.EventResponse, error) {
 	return &startgg.EventResponse{}, nil
 }
 
+func (f *FakeStartGGClient) GetBracket(slug string, page int) (*startgg.BracketResponse, error) {
+	var bracketResponse startgg.BracketResponse
+	err := json.Unmarshal([]byte(`{"data": {"event": {"sets": {"pageInfo": {"totalPages": 1}, "nodes": [
+		{"id": "preview_1_1_1", "slots": [
+			{"prereqType": "seed", "entrant": {"id": 1, "name": "Sonix", "initialSeedNum": 1, "participants": [{"gamerTag": "Sonix", "player": {"id": 11}}]}},
+			{"prereqType": "seed", "entrant": {"id": 2, "name": "Zomba", "initialSeedNum": 16, "participants": [{"gamerTag": "Zomba", "player": {"id": 12}}]}}
+		]},
+		{"id": "preview_1_2_1", "slots": [
+			{"prereqType": "set", "prereqId": "preview_1_1_1", "prereqPlacement": 1},
+			{"prereqType": "bye"}
+		]}
+	]}}}}`), &bracketResponse)
+	return &bracketResponse, err
+}
+
 type FakeFileReaderWriter struct{}
 
-func (f *FakeFileReaderWriter) ReadFile(fileName string) []byte {
+func (f *FakeFileReaderWriter) ReadFile(fileName string) ([]byte, error) {
 	data, err := os.ReadFile("../db/test_data.json")
 	if err != nil {
 		log.Fatalf("Error while reading test_data. e=%s\n", err)
 	}
-	return data
+	return data, nil
 }
 
 func (f *FakeFileReaderWriter) WriteString(filename, data string) {
@@ -83,6 +98,118 @@ func (db *InMemoryDBService) AddSets(slug string, setMapping *map[string]string)
 
 func (db *InMemoryDBService) AddSet(slug string, setId string, set string) {
 	db.storage[slug+"_"+setId] = set
+	db.storage["ingested_"+slug] = "1"
+}
+
+func (db *InMemoryDBService) GetSetSlugs() []string {
+	var slugs []string
+	for key := range db.storage {
+		if slug, ok := strings.CutPrefix(key, "ingested_"); ok {
+			slugs = append(slugs, slug)
+		}
+	}
+	slices.Sort(slugs)
+	return slugs
+}
+
+func (db *InMemoryDBService) AddEvent(slug string, event string) {
+	db.storage["events_"+slug] = event
+}
+
+func (db *InMemoryDBService) RemoveEvent(slug string) {
+	delete(db.storage, "events_"+slug)
+}
+
+func (db *InMemoryDBService) GetEvents() *map[string]string {
+	eventMapping := make(map[string]string, 0)
+	for key, event := range db.storage {
+		if slug, ok := strings.CutPrefix(key, "events_"); ok {
+			eventMapping[slug] = event
+		}
+	}
+	return &eventMapping
+}
+
+func (db *InMemoryDBService) SetEventInfo(slug, field, value string) {
+	db.storage["info_"+slug+"_"+field] = value
+}
+
+func (db *InMemoryDBService) GetEventInfo(slug, field string) string {
+	return db.storage["info_"+slug+"_"+field]
+}
+
+func (db *InMemoryDBService) AddWatchlist(id string, watchlist string) {
+	db.storage["watchlists_"+id] = watchlist
+}
+
+func (db *InMemoryDBService) RemoveWatchlist(id string) {
+	delete(db.storage, "watchlists_"+id)
+}
+
+func (db *InMemoryDBService) GetWatchlists() *map[string]string {
+	watchlistMapping := make(map[string]string, 0)
+	for key, watchlist := range db.storage {
+		if id, ok := strings.CutPrefix(key, "watchlists_"); ok {
+			watchlistMapping[id] = watchlist
+		}
+	}
+	return &watchlistMapping
+}
+
+func (db *InMemoryDBService) AddSeason(id string, season string) {
+	db.storage["seasons_"+id] = season
+}
+
+func (db *InMemoryDBService) RemoveSeason(id string) {
+	delete(db.storage, "seasons_"+id)
+}
+
+func (db *InMemoryDBService) GetSeasons() *map[string]string {
+	seasonMapping := make(map[string]string, 0)
+	for key, season := range db.storage {
+		if id, ok := strings.CutPrefix(key, "seasons_"); ok {
+			seasonMapping[id] = season
+		}
+	}
+	return &seasonMapping
+}
+
+func (db *InMemoryDBService) AddNotifiedSet(id, setKey string) bool {
+	key := "notified_" + id + "_" + setKey
+	if db.storage[key] == "1" {
+		return false
+	}
+	db.storage[key] = "1"
+	return true
+}
+
+func (db *InMemoryDBService) RemoveNotifiedSet(id, setKey string) {
+	delete(db.storage, "notified_"+id+"_"+setKey)
+}
+
+func (db *InMemoryDBService) AddRatedSet(setKey string) bool {
+	key := "rated_" + setKey
+	if db.storage[key] == "1" {
+		return false
+	}
+	db.storage[key] = "1"
+	return true
+}
+
+func (db *InMemoryDBService) GetPlayerRating(player string) string {
+	return db.storage["ratings_"+player]
+}
+
+func (db *InMemoryDBService) SetPlayerRating(player string, rating string) {
+	db.storage["ratings_"+player] = rating
+}
+
+func (db *InMemoryDBService) GetHeadToHead(players string) string {
+	return db.storage["headtohead_"+players]
+}
+
+func (db *InMemoryDBService) SetHeadToHead(players string, history string) {
+	db.storage["headtohead_"+players] = history
 }
 
 func (db *InMemoryDBService) GetSets(slug string) *map[string]string {
@@ -100,28 +227,49 @@ var service = NewService(
 	NewInMemoryDBService(),
 	fakeStartGGClient,
 	fakeFileReaderWriter,
+	PAGE_DELAY,
+	nil,
 )
 
 var slug = "tournament/smash-factor-x/event/smash-bros-ultimate-singles"
 
 func TestServiceSetsFromFile(t *testing.T) {
-	service.Process(
+	_, err := service.Process(
 		slug,
 		"Smash Factor X Ultimate Singles Upset Thread",
 		"",
 		"db/startgg_data.json",
 		"game/ultimate",
 	)
+	if err != nil {
+		t.Errorf("Expected no error, got %s", err)
+	}
 }
 
 func TestServiceSetsFromAPI(t *testing.T) {
-	service.Process(
+	_, err := service.Process(
 		slug,
 		"Smash Factor X Ultimate Singles Upset Thread",
 		"",
 		"",
 		"game/ultimate",
 	)
+	if err != nil {
+		t.Errorf("Expected no error, got %s", err)
+	}
+}
+
+func TestGetSampleUpsetThread(t *testing.T) {
+	upsetThread, err := NewService(NewInMemoryDBService(), nil, fakeFileReaderWriter, 0, nil).GetSampleUpsetThread()
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if len(upsetThread.Winners) == 0 || len(upsetThread.Losers) == 0 {
+		t.Fatalf("Expected sample upsets, got %v", upsetThread)
+	}
+	if upsetThread.Losers[0].WinnersCharacters == "" {
+		t.Errorf("Expected sample characters to be resolved without start.gg, got %v", upsetThread.Losers[0])
+	}
 }
 
 func TestSort(t *testing.T) {
@@ -159,7 +307,7 @@ func TestSort(t *testing.T) {
 }
 
 func TestDisplayMapper(t *testing.T) {
-	upsetThread := service.Process(
+	upsetThread, _ := service.Process(
 		slug,
 		"Smash Factor X Ultimate Singles Upset Thread",
 		"",
@@ -169,3 +317,41 @@ func TestDisplayMapper(t *testing.T) {
 
 	mapper.ToDisplay(upsetThread, slug)
 }
+
+func TestToDomainSetFromSlots(t *testing.T) {
+	var node startgg.Node
+	err := json.Unmarshal([]byte(`{
+		"id": 1,
+		"displayScore": "Player 3 1 - FaZe | Sparg0 3",
+		"winnerId": 2,
+		"round": 2,
+		"totalGames": 5,
+		"setGamesType": 1,
+		"games": [
+			{"id": 4, "winnerId": 2, "orderNum": 4, "entrant1Score": 0, "entrant2Score": 1},
+			{"id": 1, "winnerId": 2, "orderNum": 1},
+			{"id": 2, "winnerId": 1, "orderNum": 2},
+			{"id": 3, "winnerId": 2, "orderNum": 3}
+		],
+		"slots": [
+			{"standing": {"stats": {"score": {"value": 1}}}, "entrant": {"id": 1, "name": "Player 3", "initialSeedNum": 5, "participants": [{"gamerTag": "Player 3", "player": {"id": 10}}]}},
+			{"standing": {"stats": {"score": {"value": 3}}}, "entrant": {"id": 2, "name": "FaZe | Sparg0", "initialSeedNum": 1, "participants": [{"prefix": "FaZe", "gamerTag": "Sparg0", "player": {"id": 20}}]}}
+		]
+	}`), &node)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	set := service.toDomainSet(node, "game/ultimate")
+	if *set.Score != "3-1" || set.Outcome != domain.OutcomeScore {
+		t.Errorf("Expected score 3-1, got %s (%s)", *set.Score, set.Outcome)
+	}
+	if games := *set.Games; games[0].Id != 1 || games[3].Id != 4 || games[3].Scores[2] != 1 || games[3].Scores[1] != 0 || games[0].Scores != nil {
+		t.Errorf("Expected games in order with their scores, got %+v", games)
+	}
+	if set.GamesType != domain.GamesTypeBestOf || set.GameSlug != "game/ultimate" || set.IsNotable(domain.DefaultNotableRules) {
+		t.Errorf("Expected a best of 5 that did not go to the last game, got %+v", set)
+	}
+	if set.Winner.Prefix != "FaZe" || set.Winner.Tag != "Sparg0" || set.Loser.Prefix != "" || set.Loser.Tag != "Player 3" {
+		t.Errorf("Expected structured names, got %+v and %+v", set.Winner, set.Loser)
+	}
+}
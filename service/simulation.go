@@ -0,0 +1,73 @@
+package service
+
+import (
+	"gg/db"
+	"gg/domain"
+	"gg/mapper"
+	"log"
+	"math/rand/v2"
+	"time"
+)
+
+// cachedBracket keeps the bracket of an event between simulations, so that
+// reports do not query start.gg more often than events are polled.
+type cachedBracket struct {
+	bracket   *domain.Bracket
+	fetchedAt time.Time
+}
+
+// GetSimulationReport plays out the bracket of the tracked event and compares
+// the expected upsets with those so far.
+func (t *Tracker) GetSimulationReport(slug string, options domain.SimulationOptions) (*domain.SimulationReport, error) {
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+	event, err := t.GetEvent(slug)
+	if err != nil {
+		return nil, err
+	}
+	bracket, err := t.getBracket(slug)
+	if err != nil {
+		return nil, err
+	}
+	return SimulationReport(t.getUpsetThread(event), bracket, t.dbService, options)
+}
+
+// getBracket returns the cached bracket of the event, fetching it again once
+// it is older than the poll interval. A bracket that cannot be fetched again
+// is served stale until the next attempt, so that reports do not depend on
+// start.gg being up.
+func (t *Tracker) getBracket(slug string) (*domain.Bracket, error) {
+	t.mu.Lock()
+	cached, ok := t.brackets[slug]
+	t.mu.Unlock()
+	if ok && time.Since(cached.fetchedAt) < t.pollInterval {
+		return cached.bracket, nil
+	}
+	bracket, err := t.service.GetBracket(slug)
+	if err != nil && !ok {
+		return nil, err
+	}
+	if err != nil {
+		log.Printf("Error while fetching bracket, serving the cached one. slug=%s e=%s\n", slug, err)
+		bracket = cached.bracket
+	}
+	t.mu.Lock()
+	t.brackets[slug] = cachedBracket{bracket: bracket, fetchedAt: time.Now()}
+	t.mu.Unlock()
+	return bracket, nil
+}
+
+// SimulationReport plays out the bracket, looking up the ratings of its
+// entrants for the rating model.
+func SimulationReport(upsetThread *domain.UpsetThread, bracket *domain.Bracket, dbService db.DBServiceInterface, options domain.SimulationOptions) (*domain.SimulationReport, error) {
+	if options.Model == domain.SimulationModelRating {
+		options.Ratings = make(map[string]domain.Rating)
+		for _, entrant := range bracket.Entrants {
+			if rating := dbService.GetPlayerRating(entrant.Key()); rating != "" {
+				options.Ratings[entrant.Key()] = mapper.DBPlayerRatingToPlayerRating(rating).Rating
+			}
+		}
+	}
+	return domain.NewSimulationReport(upsetThread, bracket, options, rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64())))
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"errors"
+	"gg/client/graphql"
+	"gg/client/startgg"
+	"gg/domain"
+	"testing"
+)
+
+func TestTrackerSimulationReport(t *testing.T) {
+	var exported []*domain.UpsetThread
+	tracker := newTestTracker(&exported)
+	event := trackerEvent
+	event.Paused = true
+	tracker.AddEvent(event)
+	tracker.process(&event)
+
+	options := domain.SimulationOptions{Model: domain.SimulationModelRating, Simulations: 100}
+	if _, err := tracker.GetSimulationReport("tournament/unknown/event/singles", options); err != ErrorEventNotFound {
+		t.Errorf("Expected %s, got %v", ErrorEventNotFound, err)
+	}
+	if _, err := tracker.GetSimulationReport(slug, domain.SimulationOptions{Model: "elo", Simulations: 100}); err != domain.ErrorUnknownSimulationModel {
+		t.Errorf("Expected %s, got %v", domain.ErrorUnknownSimulationModel, err)
+	}
+	res, err := tracker.GetSimulationReport(slug, options)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if res.Title != trackerEvent.Title || res.Sets != 2 || res.Simulated != 2 || res.Played == 0 || len(res.Factors) == 0 {
+		t.Errorf("Expected the bracket to be simulated next to the sets played, got %+v", res)
+	}
+}
+
+// FlakyStartGGClient fails to return brackets once fail is set.
+type FlakyStartGGClient struct {
+	FakeStartGGClient
+	fail bool
+}
+
+func (f *FlakyStartGGClient) GetBracket(slug string, page int) (*startgg.BracketResponse, error) {
+	if f.fail {
+		return nil, &graphql.StatusError{StatusCode: 429}
+	}
+	return f.FakeStartGGClient.GetBracket(slug, page)
+}
+
+func TestTrackerSimulationReportBracketErrors(t *testing.T) {
+	client := &FlakyStartGGClient{}
+	dbService := NewInMemoryDBService()
+	newTracker := func() *Tracker {
+		tracker := NewTracker(
+			NewService(dbService, client, fakeFileReaderWriter, 0, nil),
+			dbService,
+			func(upsetThread *domain.UpsetThread) error { return nil },
+			func(webhookUrl, message string) error { return nil },
+			0,
+		)
+		// Without a poller, as brackets are fetched again on every report.
+		event := trackerEvent
+		tracker.events[slug] = &event
+		return tracker
+	}
+	tracker := newTracker()
+	options := domain.SimulationOptions{Model: domain.SimulationModelSeed, Simulations: 10}
+	if _, err := tracker.GetSimulationReport(slug, options); err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+
+	client.fail = true
+	res, err := tracker.GetSimulationReport(slug, options)
+	if err != nil || res.Sets != 2 {
+		t.Errorf("Expected the cached bracket to be served, got %+v %v", res, err)
+	}
+	var statusErr *graphql.StatusError
+	if _, err := newTracker().GetSimulationReport(slug, options); !errors.As(err, &statusErr) {
+		t.Errorf("Expected the start.gg error without a cached bracket, got %v", err)
+	}
+}
@@ -0,0 +1,405 @@
+package service
+
+import (
+	"errors"
+	"gg/db"
+	"gg/domain"
+	"gg/mapper"
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	ErrorEventNotFound      = errors.New("event is not tracked")
+	ErrorEventAlreadyExists = errors.New("event is already tracked")
+	ErrorEventSlugRequired  = errors.New("event slug is required")
+	ErrorInvalidTimezone    = errors.New("timezone is not a known IANA timezone")
+)
+
+type TrackerInterface interface {
+	Start()
+	GetEvents() []domain.Event
+	GetEvent(slug string) (*domain.Event, error)
+	AddEvent(event domain.Event) error
+	RemoveEvent(slug string) error
+	UpdateTitle(slug, title string) error
+	UpdateDisplay(slug, timezone, timeFormat string) error
+	Pause(slug string) error
+	Resume(slug string) error
+	Refresh(slug string) error
+	Export(slug string) error
+	GetUpsetThread(slug string) (*domain.UpsetThread, error)
+	UpdateWatchlists(slug string, watchlists []string) error
+	GetWatchlists() []domain.Watchlist
+	GetWatchlist(id string) (*domain.Watchlist, error)
+	AddWatchlist(watchlist domain.Watchlist) (*domain.Watchlist, error)
+	UpdateWatchlist(watchlist domain.Watchlist) error
+	RemoveWatchlist(id string) error
+	GetWatchedUpsetThreads(id string) ([]*domain.UpsetThread, error)
+	GetSeasons() []domain.Season
+	GetSeason(id string) (*domain.Season, error)
+	AddSeason(season domain.Season) (*domain.Season, error)
+	UpdateSeason(season domain.Season) error
+	RemoveSeason(id string) error
+	GetSeasonLeaderboard(id string) (*domain.SeasonLeaderboard, error)
+	GetPlayerHistory(player string) (*domain.PlayerHistory, error)
+	GetCharacterReport(slug string) (*domain.CharacterReport, error)
+	GetSeedingReport(slug string) (*domain.SeedingReport, error)
+	GetPlayerRating(player string) (*domain.PlayerRating, error)
+	GetHeadToHead(player, opponent string) (*domain.PlayerHeadToHead, error)
+	GetSimulationReport(slug string, options domain.SimulationOptions) (*domain.SimulationReport, error)
+	Subscribe(slug string) chan *domain.UpsetThread
+	Unsubscribe(slug string, ch chan *domain.UpsetThread)
+}
+
+// notificationQueueSize is how many watchlist messages can wait to be sent
+// before further sets are left for the next poll.
+const notificationQueueSize = 256
+
+type poller struct {
+	refresh chan struct{}
+	stop    chan struct{}
+}
+
+type Tracker struct {
+	service      ServiceInterface
+	dbService    db.DBServiceInterface
+	export       func(upsetThread *domain.UpsetThread) error
+	notify       func(webhookUrl, message string) error
+	pollInterval time.Duration
+	mu           sync.Mutex
+	events       map[string]*domain.Event
+	watchlists   map[string]*domain.Watchlist
+	seasons      map[string]*domain.Season
+	pollers      map[string]*poller
+	subscribers  map[string]map[chan *domain.UpsetThread]bool
+	brackets     map[string]cachedBracket
+	// stored caches the stored upset thread of every event the reports
+	// across events have read, with its stored title. An event's thread is
+	// reloaded whenever the event is polled.
+	stored map[string]*domain.UpsetThread
+	// notifications are sent by a worker started by Start, so that polling
+	// never waits on a webhook.
+	notifications chan notification
+}
+
+func NewTracker(service ServiceInterface, dbService db.DBServiceInterface, export func(upsetThread *domain.UpsetThread) error, notify func(webhookUrl, message string) error, pollInterval time.Duration) *Tracker {
+	return &Tracker{
+		service:       service,
+		dbService:     dbService,
+		export:        export,
+		notify:        notify,
+		pollInterval:  pollInterval,
+		events:        make(map[string]*domain.Event),
+		watchlists:    make(map[string]*domain.Watchlist),
+		seasons:       make(map[string]*domain.Season),
+		pollers:       make(map[string]*poller),
+		subscribers:   make(map[string]map[chan *domain.UpsetThread]bool),
+		brackets:      make(map[string]cachedBracket),
+		notifications: make(chan notification, notificationQueueSize),
+		stored:        make(map[string]*domain.UpsetThread),
+	}
+}
+
+// Start loads the stored watchlists, seasons and events and begins polling each of
+// the events and sending watchlist notifications.
+func (t *Tracker) Start() {
+	go t.sendNotifications()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for id, watchlist := range *t.dbService.GetWatchlists() {
+		t.watchlists[id] = mapper.DBWatchlistToWatchlist(watchlist)
+	}
+	for id, season := range *t.dbService.GetSeasons() {
+		t.seasons[id] = mapper.DBSeasonToSeason(season)
+	}
+	for slug, event := range *t.dbService.GetEvents() {
+		if _, ok := t.events[slug]; ok {
+			continue
+		}
+		t.events[slug] = mapper.DBEventToEvent(event)
+		t.startPoller(slug)
+	}
+}
+
+func (t *Tracker) GetEvents() []domain.Event {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	events := make([]domain.Event, 0, len(t.events))
+	for _, event := range t.events {
+		events = append(events, *event)
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Slug < events[j].Slug
+	})
+	return events
+}
+
+func (t *Tracker) GetEvent(slug string) (*domain.Event, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	event, ok := t.events[slug]
+	if !ok {
+		return nil, ErrorEventNotFound
+	}
+	res := *event
+	return &res, nil
+}
+
+func validateTimezone(timezone string) error {
+	if timezone == "" {
+		return nil
+	}
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return ErrorInvalidTimezone
+	}
+	return nil
+}
+
+func (t *Tracker) AddEvent(event domain.Event) error {
+	if event.Slug == "" {
+		return ErrorEventSlugRequired
+	}
+	if err := validateTimezone(event.Timezone); err != nil {
+		return err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.events[event.Slug]; ok {
+		return ErrorEventAlreadyExists
+	}
+	t.events[event.Slug] = &event
+	t.save(&event)
+	t.startPoller(event.Slug)
+	return nil
+}
+
+func (t *Tracker) RemoveEvent(slug string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.events[slug]; !ok {
+		return ErrorEventNotFound
+	}
+	close(t.pollers[slug].stop)
+	delete(t.pollers, slug)
+	delete(t.events, slug)
+	t.dbService.RemoveEvent(slug)
+	return nil
+}
+
+func (t *Tracker) UpdateTitle(slug, title string) error {
+	return t.update(slug, func(event *domain.Event) {
+		event.Title = title
+	})
+}
+
+// UpdateDisplay sets the timezone and time format used for the event's
+// "Last updated" time. Empty values fall back to the tournament's timezone
+// and the default format.
+func (t *Tracker) UpdateDisplay(slug, timezone, timeFormat string) error {
+	if err := validateTimezone(timezone); err != nil {
+		return err
+	}
+	return t.update(slug, func(event *domain.Event) {
+		event.Timezone = timezone
+		event.TimeFormat = timeFormat
+	})
+}
+
+func (t *Tracker) Pause(slug string) error {
+	return t.update(slug, func(event *domain.Event) {
+		event.Paused = true
+	})
+}
+
+func (t *Tracker) Resume(slug string) error {
+	if err := t.update(slug, func(event *domain.Event) {
+		event.Paused = false
+	}); err != nil {
+		return err
+	}
+	return t.Refresh(slug)
+}
+
+// Refresh wakes the event's poller so that it processes immediately instead
+// of waiting for the poll interval to elapse.
+func (t *Tracker) Refresh(slug string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	p, ok := t.pollers[slug]
+	if !ok {
+		return ErrorEventNotFound
+	}
+	select {
+	case p.refresh <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (t *Tracker) Export(slug string) error {
+	event, err := t.GetEvent(slug)
+	if err != nil {
+		return err
+	}
+	return t.export(t.getUpsetThread(event))
+}
+
+// GetUpsetThread returns the stored upset thread of the event with the
+// event's display settings and watchlists applied.
+func (t *Tracker) GetUpsetThread(slug string) (*domain.UpsetThread, error) {
+	event, err := t.GetEvent(slug)
+	if err != nil {
+		return nil, err
+	}
+	return t.getUpsetThread(event), nil
+}
+
+func (t *Tracker) getUpsetThread(event *domain.Event) *domain.UpsetThread {
+	upsetThread := t.service.GetUpsetThreadDB(event.Slug, event.Title)
+	t.applyTo(event, upsetThread)
+	return upsetThread
+}
+
+// storedUpsetThread returns the stored upset thread of the event, tracked or
+// not, titled with the given title or else its stored one.
+func (t *Tracker) storedUpsetThread(slug, title string) *domain.UpsetThread {
+	t.mu.Lock()
+	upsetThread, ok := t.stored[slug]
+	t.mu.Unlock()
+	if !ok {
+		upsetThread = t.service.GetUpsetThreadDB(slug, "")
+		t.mu.Lock()
+		// A poll that finished meanwhile stored a more recent thread.
+		if stored, ok := t.stored[slug]; ok {
+			upsetThread = stored
+		} else {
+			t.stored[slug] = upsetThread
+		}
+		t.mu.Unlock()
+	}
+	res := *upsetThread
+	if title != "" {
+		res.Title = title
+	}
+	return &res
+}
+
+// reloadStoredUpsetThread replaces the cached thread of the event with its
+// stored sets after a poll.
+func (t *Tracker) reloadStoredUpsetThread(slug string) {
+	upsetThread := t.service.GetUpsetThreadDB(slug, "")
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.stored[slug] = upsetThread
+}
+
+func (t *Tracker) Subscribe(slug string) chan *domain.UpsetThread {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	ch := make(chan *domain.UpsetThread, 1)
+	if t.subscribers[slug] == nil {
+		t.subscribers[slug] = make(map[chan *domain.UpsetThread]bool)
+	}
+	t.subscribers[slug][ch] = true
+	return ch
+}
+
+func (t *Tracker) Unsubscribe(slug string, ch chan *domain.UpsetThread) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.subscribers[slug], ch)
+}
+
+func (t *Tracker) update(slug string, fn func(event *domain.Event)) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	event, ok := t.events[slug]
+	if !ok {
+		return ErrorEventNotFound
+	}
+	fn(event)
+	t.save(event)
+	return nil
+}
+
+func (t *Tracker) save(event *domain.Event) {
+	t.dbService.AddEvent(event.Slug, mapper.EventToDBEvent(*event))
+}
+
+func (t *Tracker) startPoller(slug string) {
+	p := &poller{
+		refresh: make(chan struct{}, 1),
+		stop:    make(chan struct{}),
+	}
+	t.pollers[slug] = p
+	go t.poll(slug, p)
+}
+
+func (t *Tracker) poll(slug string, p *poller) {
+	for {
+		event, err := t.GetEvent(slug)
+		if err != nil {
+			return
+		}
+		if !event.Paused {
+			t.process(event)
+		}
+		select {
+		case <-p.stop:
+			return
+		case <-p.refresh:
+		case <-time.After(t.pollInterval):
+		}
+	}
+}
+
+func (t *Tracker) process(event *domain.Event) {
+	upsetThread, err := t.service.Process(event.Slug, event.Title, event.Subreddit, event.File, event.GameSlug)
+	now := int(time.Now().Unix())
+	if err != nil {
+		log.Printf("Error while processing event. slug=%s e=%s\n", event.Slug, err)
+		if err := t.update(event.Slug, func(event *domain.Event) {
+			event.LastError = err.Error()
+			event.LastErrorAt = now
+		}); err != nil {
+			log.Printf("Error while recording event error. slug=%s e=%s\n", event.Slug, err)
+		}
+		return
+	}
+	t.reloadStoredUpsetThread(event.Slug)
+	// The event may have been removed while it was processed, in which case
+	// nobody is told about it.
+	if err := t.update(event.Slug, func(event *domain.Event) {
+		event.LastUpdatedAt = now
+		event.LastError = ""
+		event.LastErrorAt = 0
+	}); err != nil {
+		return
+	}
+	event.LastUpdatedAt = now
+	t.applyTo(event, upsetThread)
+	t.publish(event.Slug, upsetThread)
+	t.notifyWatchlists(upsetThread)
+}
+
+// AllEvents subscribes to the updates of every tracked event.
+const AllEvents = "*"
+
+// publish hands the latest upset thread to every subscriber, replacing any
+// update the subscriber has not consumed yet so slow clients never block polling.
+func (t *Tracker) publish(slug string, upsetThread *domain.UpsetThread) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, key := range []string{slug, AllEvents} {
+		for ch := range t.subscribers[key] {
+			select {
+			case <-ch:
+			default:
+			}
+			ch <- upsetThread
+		}
+	}
+}
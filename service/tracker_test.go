@@ -0,0 +1,229 @@
+package service
+
+import (
+	"gg/client/graphql"
+	"gg/client/startgg"
+	"gg/domain"
+	"gg/mapper"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestTracker(exported *[]*domain.UpsetThread) *Tracker {
+	dbService := NewInMemoryDBService()
+	return NewTracker(
+		NewService(dbService, fakeStartGGClient, fakeFileReaderWriter, 0, nil),
+		dbService,
+		func(upsetThread *domain.UpsetThread) error {
+			*exported = append(*exported, upsetThread)
+			return nil
+		},
+		func(webhookUrl, message string) error {
+			return nil
+		},
+		time.Hour,
+	)
+}
+
+var trackerEvent = domain.Event{
+	Slug:     slug,
+	Title:    "Smash Factor X Ultimate Singles Upset Thread",
+	File:     "db/startgg_data.json",
+	GameSlug: "game/ultimate",
+}
+
+func TestTrackerAddEventPublishes(t *testing.T) {
+	var exported []*domain.UpsetThread
+	tracker := newTestTracker(&exported)
+	ch := tracker.Subscribe(slug)
+	defer tracker.Unsubscribe(slug, ch)
+
+	if err := tracker.AddEvent(trackerEvent); err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	select {
+	case upsetThread := <-ch:
+		if upsetThread.Title != trackerEvent.Title {
+			t.Errorf("Expected title %s, got %s", trackerEvent.Title, upsetThread.Title)
+		}
+		if event, _ := tracker.GetEvent(slug); upsetThread.LastUpdatedAt == 0 || upsetThread.LastUpdatedAt != event.LastUpdatedAt {
+			t.Errorf("Expected the thread to be stamped with the poll, got %d", upsetThread.LastUpdatedAt)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected an upset thread to be published")
+	}
+	if err := tracker.AddEvent(trackerEvent); err != ErrorEventAlreadyExists {
+		t.Errorf("Expected %s, got %v", ErrorEventAlreadyExists, err)
+	}
+}
+
+func TestTrackerPauseAndTitle(t *testing.T) {
+	var exported []*domain.UpsetThread
+	tracker := newTestTracker(&exported)
+	event := trackerEvent
+	event.Paused = true
+	tracker.AddEvent(event)
+
+	tracker.UpdateTitle(slug, "New title")
+	tracker.Pause(slug)
+
+	stored := mapper.DBEventToEvent((*tracker.dbService.GetEvents())[slug])
+	if !stored.Paused {
+		t.Errorf("Expected stored event to be paused")
+	}
+	if stored.Title != "New title" {
+		t.Errorf("Expected title %s, got %s", "New title", stored.Title)
+	}
+
+	if err := tracker.Export(slug); err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if len(exported) != 1 || exported[0].Title != "New title" {
+		t.Errorf("Expected one export titled %s, got %v", "New title", exported)
+	}
+}
+
+func TestTrackerRemoveEvent(t *testing.T) {
+	var exported []*domain.UpsetThread
+	tracker := newTestTracker(&exported)
+	event := trackerEvent
+	event.Paused = true
+	tracker.AddEvent(event)
+
+	if err := tracker.RemoveEvent(slug); err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if len(tracker.GetEvents()) != 0 {
+		t.Errorf("Expected no events, got %v", tracker.GetEvents())
+	}
+	if len(*tracker.dbService.GetEvents()) != 0 {
+		t.Errorf("Expected no stored events")
+	}
+	for _, fn := range []func(string) error{tracker.RemoveEvent, tracker.Pause, tracker.Resume, tracker.Refresh, tracker.Export} {
+		if err := fn(slug); err != ErrorEventNotFound {
+			t.Errorf("Expected %s, got %v", ErrorEventNotFound, err)
+		}
+	}
+}
+
+func TestTrackerStartLoadsEvents(t *testing.T) {
+	var exported []*domain.UpsetThread
+	tracker := newTestTracker(&exported)
+	event := trackerEvent
+	event.Paused = true
+	tracker.dbService.AddEvent(slug, mapper.EventToDBEvent(event))
+
+	tracker.Start()
+
+	if _, err := tracker.GetEvent(slug); err != nil {
+		t.Errorf("Expected stored event to be loaded, got %s", err)
+	}
+}
+
+func TestTrackerUpdateDisplay(t *testing.T) {
+	var exported []*domain.UpsetThread
+	tracker := newTestTracker(&exported)
+	event := trackerEvent
+	event.Paused = true
+	tracker.AddEvent(event)
+
+	if err := tracker.UpdateDisplay(slug, "Mars/Olympus_Mons", ""); err != ErrorInvalidTimezone {
+		t.Errorf("Expected %s, got %v", ErrorInvalidTimezone, err)
+	}
+	if err := tracker.UpdateDisplay(slug, "Europe/Berlin", "2006-01-02 15:04"); err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	tracker.Export(slug)
+	if exported[0].Timezone != "Europe/Berlin" || exported[0].TimeFormat != "2006-01-02 15:04" {
+		t.Errorf("Expected event display settings on export, got %s %s", exported[0].Timezone, exported[0].TimeFormat)
+	}
+}
+
+func TestTrackerRequiresSlug(t *testing.T) {
+	var exported []*domain.UpsetThread
+	tracker := newTestTracker(&exported)
+	if err := tracker.AddEvent(domain.Event{}); err != ErrorEventSlugRequired {
+		t.Errorf("Expected %s, got %v", ErrorEventSlugRequired, err)
+	}
+	if err := tracker.AddEvent(domain.Event{Slug: slug, Timezone: "Nowhere"}); err != ErrorInvalidTimezone {
+		t.Errorf("Expected %s, got %v", ErrorInvalidTimezone, err)
+	}
+}
+
+// FailingStartGGClient answers every query as start.gg does when it is rate
+// limited.
+type FailingStartGGClient struct {
+	FakeStartGGClient
+}
+
+func (f *FailingStartGGClient) GetEvent(slug string, page int) (*startgg.EventResponse, error) {
+	return nil, &graphql.StatusError{StatusCode: 429}
+}
+
+func TestTrackerRecordsErrors(t *testing.T) {
+	dbService := NewInMemoryDBService()
+	tracker := NewTracker(
+		NewService(dbService, &FailingStartGGClient{}, fakeFileReaderWriter, 0, nil),
+		dbService,
+		func(upsetThread *domain.UpsetThread) error { return nil },
+		func(webhookUrl, message string) error { return nil },
+		time.Hour,
+	)
+	failing := domain.Event{Slug: "tournament/failing/event/singles", Paused: true}
+	event := trackerEvent
+	event.Paused = true
+	tracker.AddEvent(failing)
+	tracker.AddEvent(event)
+
+	tracker.process(&failing)
+	res, err := tracker.GetEvent(failing.Slug)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if !strings.Contains(res.LastError, "status_code=429") || res.LastErrorAt == 0 {
+		t.Errorf("Expected the start.gg error to be recorded, got %+v", res)
+	}
+
+	tracker.process(&event)
+	if res, _ := tracker.GetEvent(event.Slug); res.LastError != "" || res.LastUpdatedAt == 0 {
+		t.Errorf("Expected the other event to keep processing, got %+v", res)
+	}
+
+	// The failing event recovers once its sets can be read.
+	failing.File = trackerEvent.File
+	tracker.process(&failing)
+	if res, _ := tracker.GetEvent(failing.Slug); res.LastError != "" || res.LastErrorAt != 0 || res.LastUpdatedAt == 0 {
+		t.Errorf("Expected the error to be cleared after a successful poll, got %+v", res)
+	}
+}
+
+func TestTrackerProcessRemovedEvent(t *testing.T) {
+	var exported []*domain.UpsetThread
+	tracker := newTestTracker(&exported)
+	notified := 0
+	tracker.notify = func(webhookUrl, message string) error {
+		notified++
+		return nil
+	}
+	tracker.AddWatchlist(domain.Watchlist{Name: "Mexico", Players: []string{"Cesc", "NeoMX/ST | Daige"}, WebhookUrl: "https://example.com/webhook"})
+	event := trackerEvent
+	event.Paused = true
+	tracker.AddEvent(event)
+	ch := tracker.Subscribe(AllEvents)
+	defer tracker.Unsubscribe(AllEvents, ch)
+
+	// The poll was already processing when the event was removed.
+	tracker.RemoveEvent(slug)
+	tracker.process(&event)
+
+	select {
+	case upsetThread := <-ch:
+		t.Errorf("Expected no update for a removed event, got %s", upsetThread.Slug)
+	default:
+	}
+	sendQueued(tracker)
+	if notified != 0 {
+		t.Errorf("Expected no notifications for a removed event, got %d", notified)
+	}
+}
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"errors"
+	"gg/domain"
+	"gg/mapper"
+	"log"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	ErrorWatchlistNotFound      = errors.New("watchlist not found")
+	ErrorWatchlistAlreadyExists = errors.New("watchlist already exists")
+	ErrorWatchlistNameRequired  = errors.New("watchlist name is required")
+	ErrorInvalidWebhookUrl      = errors.New("webhook url must be an http or https url")
+)
+
+var nonAlphanumeric = regexp.MustCompile(`[^a-z0-9]+`)
+
+// idFromName derives the ID used in the URLs of a watchlist or season from
+// its name.
+func idFromName(name string) string {
+	return strings.Trim(nonAlphanumeric.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
+
+func validateWatchlist(watchlist domain.Watchlist) error {
+	if strings.TrimSpace(watchlist.Name) == "" {
+		return ErrorWatchlistNameRequired
+	}
+	if url := watchlist.WebhookUrl; url != "" && !strings.HasPrefix(url, "https://") && !strings.HasPrefix(url, "http://") {
+		return ErrorInvalidWebhookUrl
+	}
+	return nil
+}
+
+func (t *Tracker) GetWatchlists() []domain.Watchlist {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	watchlists := make([]domain.Watchlist, 0, len(t.watchlists))
+	for _, watchlist := range t.watchlists {
+		watchlists = append(watchlists, *watchlist)
+	}
+	sort.Slice(watchlists, func(i, j int) bool {
+		return watchlists[i].Id < watchlists[j].Id
+	})
+	return watchlists
+}
+
+func (t *Tracker) GetWatchlist(id string) (*domain.Watchlist, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	watchlist, ok := t.watchlists[id]
+	if !ok {
+		return nil, ErrorWatchlistNotFound
+	}
+	res := *watchlist
+	return &res, nil
+}
+
+// AddWatchlist stores a new watchlist. Its ID is derived from the name when
+// it is not given, and only sets completed from now on are notified.
+func (t *Tracker) AddWatchlist(watchlist domain.Watchlist) (*domain.Watchlist, error) {
+	if err := validateWatchlist(watchlist); err != nil {
+		return nil, err
+	}
+	if watchlist.Id == "" {
+		watchlist.Id = idFromName(watchlist.Name)
+	}
+	if watchlist.Id == "" {
+		return nil, ErrorWatchlistNameRequired
+	}
+	watchlist.CreatedAt = int(time.Now().Unix())
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.watchlists[watchlist.Id]; ok {
+		return nil, ErrorWatchlistAlreadyExists
+	}
+	t.watchlists[watchlist.Id] = &watchlist
+	t.dbService.AddWatchlist(watchlist.Id, mapper.WatchlistToDBWatchlist(watchlist))
+	res := watchlist
+	return &res, nil
+}
+
+// UpdateWatchlist replaces the name, players and webhook of the watchlist
+// with the same ID.
+func (t *Tracker) UpdateWatchlist(watchlist domain.Watchlist) error {
+	if err := validateWatchlist(watchlist); err != nil {
+		return err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	stored, ok := t.watchlists[watchlist.Id]
+	if !ok {
+		return ErrorWatchlistNotFound
+	}
+	stored.Name = watchlist.Name
+	stored.Players = watchlist.Players
+	stored.WebhookUrl = watchlist.WebhookUrl
+	t.dbService.AddWatchlist(stored.Id, mapper.WatchlistToDBWatchlist(*stored))
+	return nil
+}
+
+// RemoveWatchlist deletes the watchlist and takes it off every event's thread.
+func (t *Tracker) RemoveWatchlist(id string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.watchlists[id]; !ok {
+		return ErrorWatchlistNotFound
+	}
+	delete(t.watchlists, id)
+	t.dbService.RemoveWatchlist(id)
+	for _, event := range t.events {
+		if slices.Contains(event.Watchlists, id) {
+			event.Watchlists = slices.DeleteFunc(slices.Clone(event.Watchlists), func(watchlist string) bool {
+				return watchlist == id
+			})
+			t.save(event)
+		}
+	}
+	return nil
+}
+
+// UpdateWatchlists sets the watchlists whose sets are shown in the event's
+// thread.
+func (t *Tracker) UpdateWatchlists(slug string, watchlists []string) error {
+	t.mu.Lock()
+	for _, id := range watchlists {
+		if _, ok := t.watchlists[id]; !ok {
+			t.mu.Unlock()
+			return ErrorWatchlistNotFound
+		}
+	}
+	t.mu.Unlock()
+	return t.update(slug, func(event *domain.Event) {
+		event.Watchlists = watchlists
+	})
+}
+
+// GetWatchedUpsetThreads returns the upset thread of every tracked event
+// that has sets of the watchlist's players, with only those sets watched.
+func (t *Tracker) GetWatchedUpsetThreads(id string) ([]*domain.UpsetThread, error) {
+	watchlist, err := t.GetWatchlist(id)
+	if err != nil {
+		return nil, err
+	}
+	var res []*domain.UpsetThread
+	for _, event := range t.GetEvents() {
+		upsetThread := t.service.GetUpsetThreadDB(event.Slug, event.Title)
+		event.ApplyTo(upsetThread)
+		upsetThread.Watched = domain.WatchedItems(upsetThread, []domain.Watchlist{*watchlist})
+		if len(upsetThread.Watched) > 0 {
+			res = append(res, upsetThread)
+		}
+	}
+	return res, nil
+}
+
+func (t *Tracker) applyTo(event *domain.Event, upsetThread *domain.UpsetThread) {
+	event.ApplyTo(upsetThread)
+	var watchlists []domain.Watchlist
+	t.mu.Lock()
+	for _, id := range event.Watchlists {
+		if watchlist, ok := t.watchlists[id]; ok {
+			watchlists = append(watchlists, *watchlist)
+		}
+	}
+	t.mu.Unlock()
+	if len(watchlists) > 0 {
+		upsetThread.Watched = domain.WatchedItems(upsetThread, watchlists)
+	}
+}
+
+// notification is a watchlist message waiting to be sent for a set.
+type notification struct {
+	watchlistId, webhookUrl, setKey, message string
+}
+
+// notifyWatchlists queues for every watchlist with a webhook the sets of its
+// players completed since it was created, once per set.
+func (t *Tracker) notifyWatchlists(upsetThread *domain.UpsetThread) {
+	for _, watchlist := range t.GetWatchlists() {
+		if watchlist.WebhookUrl == "" {
+			continue
+		}
+		items := domain.WatchedItems(upsetThread, []domain.Watchlist{watchlist})
+		// Oldest first, so that the messages arrive in the order the sets were played.
+		slices.Reverse(items)
+		for _, item := range items {
+			setKey := upsetThread.Slug + "/" + item.Id
+			if item.CompletedAt < watchlist.CreatedAt || !t.dbService.AddNotifiedSet(watchlist.Id, setKey) {
+				continue
+			}
+			// The set is marked before it is queued so that it is sent once,
+			// and unmarked when it cannot be sent so that the next poll
+			// retries it.
+			n := notification{watchlist.Id, watchlist.WebhookUrl, setKey, mapper.ToWatchlistMessage(watchlist, upsetThread, item)}
+			select {
+			case t.notifications <- n:
+			default:
+				log.Printf("Watchlist notification queue is full. id=%s set=%s\n", watchlist.Id, item.Id)
+				t.dbService.RemoveNotifiedSet(watchlist.Id, setKey)
+			}
+		}
+	}
+}
+
+// sendNotifications sends the queued watchlist notifications one at a time.
+func (t *Tracker) sendNotifications() {
+	for n := range t.notifications {
+		t.send(n)
+	}
+}
+
+func (t *Tracker) send(n notification) {
+	if err := t.notify(n.webhookUrl, n.message); err != nil {
+		log.Printf("Error while notifying watchlist. id=%s set=%s e=%s\n", n.watchlistId, n.setKey, err)
+		t.dbService.RemoveNotifiedSet(n.watchlistId, n.setKey)
+	}
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"errors"
+	"gg/domain"
+	"strings"
+	"testing"
+)
+
+func TestTrackerWatchlists(t *testing.T) {
+	var exported []*domain.UpsetThread
+	tracker := newTestTracker(&exported)
+
+	watchlist, err := tracker.AddWatchlist(domain.Watchlist{Name: "Team Liquid!", Players: []string{"sonix"}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if watchlist.Id != "team-liquid" {
+		t.Errorf("Expected id team-liquid, got %s", watchlist.Id)
+	}
+	if _, err := tracker.AddWatchlist(domain.Watchlist{Name: "Team Liquid"}); err != ErrorWatchlistAlreadyExists {
+		t.Errorf("Expected %s, got %v", ErrorWatchlistAlreadyExists, err)
+	}
+	if _, err := tracker.AddWatchlist(domain.Watchlist{Name: "Team", WebhookUrl: "ftp://example.com"}); err != ErrorInvalidWebhookUrl {
+		t.Errorf("Expected %s, got %v", ErrorInvalidWebhookUrl, err)
+	}
+	if _, err := tracker.AddWatchlist(domain.Watchlist{Name: " "}); err != ErrorWatchlistNameRequired {
+		t.Errorf("Expected %s, got %v", ErrorWatchlistNameRequired, err)
+	}
+
+	event := trackerEvent
+	event.Paused = true
+	tracker.AddEvent(event)
+	if err := tracker.UpdateWatchlists(slug, []string{"unknown"}); err != ErrorWatchlistNotFound {
+		t.Errorf("Expected %s, got %v", ErrorWatchlistNotFound, err)
+	}
+	if err := tracker.UpdateWatchlists(slug, []string{"team-liquid"}); err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	tracker.process(&event)
+	upsetThread, _ := tracker.GetUpsetThread(slug)
+	if len(upsetThread.Watched) != 1 || upsetThread.Watched[0].LosersName != "Sonix" {
+		t.Errorf("Expected the set of Sonix to be watched, got %v", upsetThread.Watched)
+	}
+
+	if err := tracker.RemoveWatchlist("team-liquid"); err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if stored, _ := tracker.GetEvent(slug); len(stored.Watchlists) != 0 {
+		t.Errorf("Expected the watchlist to be removed from the event, got %v", stored.Watchlists)
+	}
+	if len(*tracker.dbService.GetWatchlists()) != 0 {
+		t.Errorf("Expected no stored watchlists")
+	}
+}
+
+// sendQueued sends the queued notifications as the worker started by Start
+// would.
+func sendQueued(tracker *Tracker) {
+	for {
+		select {
+		case n := <-tracker.notifications:
+			tracker.send(n)
+		default:
+			return
+		}
+	}
+}
+
+func TestTrackerNotifiesWatchlistsOnce(t *testing.T) {
+	var exported []*domain.UpsetThread
+	tracker := newTestTracker(&exported)
+	var messages []string
+	tracker.notify = func(webhookUrl, message string) error {
+		messages = append(messages, message)
+		return nil
+	}
+	tracker.AddWatchlist(domain.Watchlist{Name: "Mexico", Players: []string{"Cesc", "NeoMX/ST | Daige"}, WebhookUrl: "https://example.com/webhook"})
+	// Sets completed before the watchlist was created are not notified.
+	tracker.watchlists["mexico"].CreatedAt = 1690677324
+	event := trackerEvent
+	event.Paused = true
+	tracker.AddEvent(event)
+
+	tracker.process(&event)
+	sendQueued(tracker)
+	tracker.process(&event)
+	sendQueued(tracker)
+
+	if len(messages) != 2 {
+		t.Fatalf("Expected 2 messages, got %q", messages)
+	}
+	if !strings.HasPrefix(messages[0], "Mexico: ") || !strings.Contains(messages[0], "Daige") {
+		t.Errorf("Expected the earliest set first, got %q", messages[0])
+	}
+	if !strings.Contains(messages[1], "https://www.start.gg/"+slug+"/set/63302992") {
+		t.Errorf("Expected a link to the set, got %q", messages[1])
+	}
+}
+
+func TestTrackerRetriesFailedNotifications(t *testing.T) {
+	var exported []*domain.UpsetThread
+	tracker := newTestTracker(&exported)
+	var messages []string
+	failing := true
+	tracker.notify = func(webhookUrl, message string) error {
+		if failing {
+			return errors.New("webhook timed out")
+		}
+		messages = append(messages, message)
+		return nil
+	}
+	tracker.AddWatchlist(domain.Watchlist{Name: "Mexico", Players: []string{"Cesc", "NeoMX/ST | Daige"}, WebhookUrl: "https://example.com/webhook"})
+	tracker.watchlists["mexico"].CreatedAt = 1690677324
+	event := trackerEvent
+	event.Paused = true
+	tracker.AddEvent(event)
+
+	tracker.process(&event)
+	sendQueued(tracker)
+	failing = false
+	tracker.process(&event)
+	sendQueued(tracker)
+	tracker.process(&event)
+	sendQueued(tracker)
+
+	if len(messages) != 2 {
+		t.Errorf("Expected the failed messages to be sent once on the next poll, got %q", messages)
+	}
+}
+
+func TestTrackerQueuesNotifications(t *testing.T) {
+	var exported []*domain.UpsetThread
+	tracker := newTestTracker(&exported)
+	tracker.notifications = make(chan notification, 1)
+	var messages []string
+	tracker.notify = func(webhookUrl, message string) error {
+		messages = append(messages, message)
+		return nil
+	}
+	tracker.AddWatchlist(domain.Watchlist{Name: "Mexico", Players: []string{"Cesc", "NeoMX/ST | Daige"}, WebhookUrl: "https://example.com/webhook"})
+	tracker.watchlists["mexico"].CreatedAt = 1690677324
+	event := trackerEvent
+	event.Paused = true
+	tracker.AddEvent(event)
+
+	// Nothing is sent while polling, and the set that does not fit in the
+	// queue is left for the next poll.
+	tracker.process(&event)
+	if len(messages) != 0 || len(tracker.notifications) != 1 {
+		t.Fatalf("Expected 1 queued message and none sent, got %d queued and %q", len(tracker.notifications), messages)
+	}
+	sendQueued(tracker)
+	tracker.process(&event)
+	sendQueued(tracker)
+	tracker.process(&event)
+	sendQueued(tracker)
+	if len(messages) != 2 {
+		t.Errorf("Expected both messages to be sent once, got %q", messages)
+	}
+}
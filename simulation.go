@@ -0,0 +1,58 @@
+package main
+
+import (
+	"gg/domain"
+	"gg/service"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+type SimulationHandler struct {
+	tracker   service.TrackerInterface
+	templates *templates
+}
+
+// handleSimulations registers the upset simulation of each tracked event.
+func handleSimulations(tracker service.TrackerInterface, templates *templates) {
+	http.Handle("GET /event/tournament/{tournament}/event/{event}/simulation", &SimulationHandler{tracker: tracker, templates: templates})
+}
+
+// ServeHTTP plays out the bracket with ?model=seed, the default, or
+// ?model=rating, ?simulations times.
+func (h *SimulationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	format, ok := reportFormat(w, r)
+	if !ok {
+		return
+	}
+	options := domain.SimulationOptions{Model: domain.SimulationModelSeed, Simulations: domain.DefaultSimulations}
+	if model := r.URL.Query().Get("model"); model != "" {
+		options.Model = model
+	}
+	if simulations := r.URL.Query().Get("simulations"); simulations != "" {
+		n, err := strconv.Atoi(simulations)
+		if err != nil {
+			http.Error(w, domain.ErrorInvalidSimulations.Error(), http.StatusBadRequest)
+			return
+		}
+		options.Simulations = n
+	}
+	slug := eventSlug(r)
+	report, err := h.tracker.GetSimulationReport(slug, options)
+	switch err {
+	case nil:
+	case service.ErrorEventNotFound:
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	case domain.ErrorUnknownSimulationModel, domain.ErrorInvalidSimulations:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	default:
+		log.Printf("Error while simulating event. slug=%s e=%s\n", slug, err)
+		http.Error(w, "Error while simulating event", http.StatusBadGateway)
+		return
+	}
+	if err := writeReport(w, h.templates.simulation, report, format); err != nil {
+		log.Printf("Error while rendering simulation. slug=%s e=%s\n", slug, err)
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"gg/domain"
+	"gg/mapper"
+	"gg/service"
+	"log"
+	"net/http"
+	"time"
+)
+
+type WatchlistHandler struct {
+	tracker   service.TrackerInterface
+	templates *templates
+}
+
+// handleWatchlists registers the live page of every watchlist.
+func handleWatchlists(tracker service.TrackerInterface, templates *templates) {
+	h := &WatchlistHandler{tracker: tracker, templates: templates}
+	http.HandleFunc("GET /watchlist/{id}", h.page)
+}
+
+// watchlistDisplay gathers the watched sets of every tracked event.
+func watchlistDisplay(tracker service.TrackerInterface, templates *templates, id, host string) (*domain.WatchlistDisplay, error) {
+	watchlist, err := tracker.GetWatchlist(id)
+	if err != nil {
+		return nil, err
+	}
+	upsetThreads, err := tracker.GetWatchedUpsetThreads(id)
+	if err != nil {
+		return nil, err
+	}
+	return mapper.ToWatchlistDisplay(*watchlist, upsetThreads, host, templates.displayOptions["html"]), nil
+}
+
+func (h *WatchlistHandler) page(w http.ResponseWriter, r *http.Request) {
+	display, err := watchlistDisplay(h.tracker, h.templates, r.PathValue("id"), r.Host)
+	if err != nil {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := h.templates.watchlistHTML.Execute(w, display); err != nil {
+		log.Printf("Error while rendering watchlist. id=%s e=%s\n", display.Id, err)
+	}
+}
+
+var webhookClient = &http.Client{Timeout: 10 * time.Second}
+
+// postWebhook sends a message to a Discord compatible webhook.
+func postWebhook(webhookUrl, message string) error {
+	body, err := json.Marshal(map[string]string{"content": message})
+	if err != nil {
+		return err
+	}
+	res, err := webhookClient.Post(webhookUrl, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with %s", res.Status)
+	}
+	return nil
+}